@@ -3,8 +3,8 @@
 package path
 
 import (
+	"fmt"
 	"reflect"
-	"strings"
 )
 
 // Translate replaces any path component that have an alias within the given
@@ -21,30 +21,176 @@ func (path P) Translate(aliases map[string]string) (result P) {
 }
 
 // JSONAliases crawls the given type and returns an alias map from JSON names to
-// struct field names.
+// struct field names. Field visibility and naming follow the rules of
+// encoding/json. Note that the alias map is shared by all the structs
+// reachable from the given type which means that JSON names used by different
+// structs for different fields will collide. TranslateJSON should be preferred
+// as it doesn't suffer from this limitation.
 func JSONAliases(typ reflect.Type) map[string]string {
+	return TagAliases(typ, "json")
+}
+
+// TagAliases crawls the given type and returns an alias map from the names
+// given by the tagName struct tag to struct field names. The naming rules of the
+// common encoders are followed for the json, yaml, bson, xml, toml and msgpack
+// tags. Other tags follow the same rules as json along with support for the
+// ',inline' option. Since an alias map can only translate a single component
+// into another, the fields of inlined structs and nested xml names are mapped
+// using the last component of their name and path. As with JSONAliases, the
+// alias map is shared by all the structs reachable from the given type and
+// TranslateTag should be preferred.
+func TagAliases(typ reflect.Type, tagName string) map[string]string {
 	aliases := make(map[string]string)
-	jsonAliases(typ, aliases)
+	tagAliases(typ, tagName, aliases, map[reflect.Type]bool{})
 	return aliases
 }
 
-func jsonAliases(typ reflect.Type, aliases map[string]string) {
+func tagAliases(typ reflect.Type, tag string, aliases map[string]string, visited map[reflect.Type]bool) {
+	if visited[typ] {
+		return
+	}
+	visited[typ] = true
+
 	switch typ.Kind() {
 
 	case reflect.Chan, reflect.Ptr, reflect.Map, reflect.Array, reflect.Slice:
-		jsonAliases(typ.Elem(), aliases)
+		tagAliases(typ.Elem(), tag, aliases, visited)
 
 	case reflect.Struct:
+		for _, field := range tagFields(typ, tag) {
+			aliases[field.names.Last()] = field.path.Last()
+			tagAliases(field.typ, tag, aliases, visited)
+		}
+	}
+}
 
-		for i := 0; i < typ.NumField(); i++ {
-			field := typ.Field(i)
+// TranslateJSON translates a JSON path into a path usable by gopath. See
+// TranslateTag for more details.
+func (path P) TranslateJSON(typ reflect.Type) (P, error) {
+	return path.TranslateTag(typ, "json")
+}
+
+// TranslateTag translates a path made of names given by the tagName struct tag
+// into a path usable by gopath by walking the given type alongside the path and
+// resolving each component against the fields of the struct at that
+// position. Indexes, map keys and channel components are left untouched.
+// Returns an error if a component doesn't match any of the fields of a
+// struct. Components following an interface or a wildcard on a struct can't be
+// resolved statically and are left untouched. See TagAliases for the list of
+// supported tags.
+func (path P) TranslateTag(typ reflect.Type, tagName string) (P, error) {
+	return translateType(typ, tagName, false, P{}, path)
+}
 
-			list := strings.Split(field.Tag.Get("json"), ",")
-			if len(list) > 0 {
-				aliases[list[0]] = field.Name
+// TranslateToJSON translates a path usable by gopath into a JSON path. See
+// TranslateToTag for more details.
+func (path P) TranslateToJSON(typ reflect.Type) (P, error) {
+	return path.TranslateToTag(typ, "json")
+}
+
+// TranslateToTag is the reverse of TranslateTag and translates a path usable by
+// gopath into a path made of the names given by the tagName struct tag. Fields
+// promoted from embedded structs can be referenced either directly or through
+// their embedded struct. Indexes, map keys and channel components are left
+// untouched. Returns an error if a component refers to a field that doesn't
+// exist or that has no name for the given tag.
+func (path P) TranslateToTag(typ reflect.Type, tagName string) (P, error) {
+	return translateType(typ, tagName, true, P{}, path)
+}
+
+func translateType(typ reflect.Type, tag string, reverse bool, head, tail P) (P, error) {
+	for len(tail) > 0 {
+		typ = indirectType(typ)
+		mid := tail[0]
+
+		switch typ.Kind() {
+
+		case reflect.Interface:
+			return append(head, tail...), nil
+
+		case reflect.Struct:
+			if mid == "*" {
+				return append(head, tail...), nil
+			}
+
+			if reverse {
+				tail = expandPromoted(typ, tail)
 			}
 
-			jsonAliases(field.Type, aliases)
+			field, ok := matchTagField(tagFields(typ, tag), tail, reverse)
+			if !ok {
+				return nil, fmt.Errorf("no field '%s' in type '%s' at '%s'", mid, typ, head)
+			}
+
+			if reverse {
+				head, tail = append(head, field.names...), tail[len(field.path):]
+			} else {
+				head, tail = append(head, field.path...), tail[len(field.names):]
+			}
+
+			typ = field.typ
+			continue
+
+		case reflect.Array, reflect.Slice, reflect.Map, reflect.Chan:
+			head, typ = append(head, mid), typ.Elem()
+
+		case reflect.Func:
+			if mid != "()" || typ.NumOut() == 0 {
+				return nil, fmt.Errorf("invalid function component '%s' in type '%s' at '%s'", mid, typ, head)
+			}
+
+			head, typ = append(head, mid), typ.Out(0)
+
+		default:
+			return nil, fmt.Errorf("unable to path through type '%s' at '%s'", typ, head)
+		}
+
+		tail = tail[1:]
+	}
+
+	return head, nil
+}
+
+// expandPromoted replaces the first component of the path with the full path
+// of the field if it's promoted from an embedded struct.
+func expandPromoted(typ reflect.Type, path P) P {
+	field, ok := typ.FieldByName(path[0])
+	if !ok || len(field.Index) == 1 {
+		return path
+	}
+
+	var result P
+	for i := range field.Index {
+		result = append(result, typ.FieldByIndex(field.Index[:i+1]).Name)
+	}
+
+	return append(result, path[1:]...)
+}
+
+// matchTagField returns the field whose names, or go path if reverse is set,
+// are a prefix of the path. The longest match wins to support nested names.
+func matchTagField(fields []tagField, path P, reverse bool) (result tagField, ok bool) {
+	var best int
+
+	for _, field := range fields {
+		key := field.names
+		if reverse {
+			key = field.path
+		}
+
+		if len(key) > len(path) || len(key) <= best {
+			continue
+		}
+
+		match := true
+		for i, name := range key {
+			match = match && path[i] == name
+		}
+
+		if match {
+			result, ok, best = field, true, len(key)
 		}
 	}
+
+	return
 }
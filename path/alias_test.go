@@ -3,7 +3,11 @@
 package path
 
 import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -27,6 +31,274 @@ func TestPathTranslate(t *testing.T) {
 	translate(t, aliases, "zebra.alice.wall", "zebra.A.wall")
 }
 
+func TestPathTranslateToJSON(t *testing.T) {
+	obj := struct {
+		A *struct {
+			B int `json:"bob"`
+		} `json:"alice,omitempty"`
+		C []*struct {
+			D map[string]*struct {
+				E int `json:"eve"`
+			} `json:"dan"`
+		} `json:"charlie"`
+		F int `json:"-"`
+		G interface{}
+	}{}
+
+	typ := reflect.TypeOf(obj)
+
+	translateToTag(t, typ, "json", "A.B", "alice.bob")
+	translateToTag(t, typ, "json", "C.0.D.x.E", "charlie.0.dan.x.eve")
+	translateToTag(t, typ, "json", "C.*.D.*.E", "charlie.*.dan.*.eve")
+	translateToTag(t, typ, "json", "G.X.Y", "G.X.Y")
+	translateToTagFail(t, typ, "json", "F")
+	translateToTagFail(t, typ, "json", "alice")
+	translateToTagFail(t, typ, "json", "A.B.C")
+
+	outer := reflect.TypeOf(JSONOuter{})
+	translateToTag(t, outer, "json", "P", "p")
+	translateToTag(t, outer, "json", "JSONPtr.P", "p")
+	translateToTag(t, outer, "json", "jsonInner.C", "C")
+	translateToTag(t, outer, "json", "D", "-")
+	translateToTag(t, outer, "json", "Map.k", "map.k")
+	translateToTagFail(t, outer, "json", "C")
+	translateToTagFail(t, outer, "json", "JSONPtr.X")
+
+	tagged := reflect.TypeOf(TagStruct{})
+	translateToTag(t, tagged, "yaml", "Base.ID", "id")
+	translateToTag(t, tagged, "yaml", "ID", "tagbase.id")
+	translateToTag(t, tagged, "yaml", "Items.0.Value", "items.0.val")
+	translateToTag(t, tagged, "xml", "Name", "info.name")
+	translateToTag(t, tagged, "xml", "Kind", "meta.kind")
+	translateToTag(t, tagged, "xml", "Base.Kind", "Base.meta.kind")
+	translateToTagFail(t, tagged, "xml", "Body")
+	translateToTagFail(t, tagged, "yaml", "Hidden")
+}
+
+func translateToTag(t *testing.T, typ reflect.Type, tag, old, exp string) {
+	path, err := New(old).TranslateToTag(typ, tag)
+	if err != nil {
+		t.Errorf("FAIL(%s): %s -> %s", tag, old, err)
+	} else if path.String() != exp {
+		t.Errorf("FAIL(%s): %s -> %s != %s ", tag, old, path, exp)
+	}
+}
+
+func translateToTagFail(t *testing.T, typ reflect.Type, tag, old string) {
+	if path, err := New(old).TranslateToTag(typ, tag); err == nil {
+		t.Errorf("FAIL(%s): %s -> %s expected error", tag, old, path)
+	}
+}
+
+func TestPathTranslateJSON(t *testing.T) {
+	type Item struct {
+		ID   int `json:"id"`
+		Name string
+		Skip int `json:"-"`
+	}
+
+	obj := struct {
+		ID    string  `json:"id"`
+		Items []*Item `json:"items"`
+		Index map[string]struct {
+			Key string `json:"id"`
+		} `json:"index"`
+		Any interface{}           `json:"any"`
+		Fn  func() (*Item, error) `json:"fn"`
+	}{}
+
+	typ := reflect.TypeOf(&obj)
+
+	translateJSON(t, typ, "id", "ID")
+	translateJSON(t, typ, "items.0.id", "Items.0.ID")
+	translateJSON(t, typ, "items.*.Name", "Items.*.Name")
+	translateJSON(t, typ, "index.x.id", "Index.x.Key")
+	translateJSON(t, typ, "any.id.foo", "Any.id.foo")
+	translateJSON(t, typ, "fn.().id", "Fn.().ID")
+
+	translateJSONFail(t, typ, "ID")
+	translateJSONFail(t, typ, "items.0.Skip")
+	translateJSONFail(t, typ, "items.0.id.x")
+	translateJSONFail(t, typ, "fn.id")
+}
+
+type TagBase struct {
+	ID   int    `yaml:"id" bson:"_id" xml:"id,attr" custom:"ident"`
+	Kind string `yaml:"kind,omitempty" bson:",omitempty" xml:"meta>kind" toml:"kind"`
+}
+
+type TagStruct struct {
+	XMLName xml.Name `xml:"root"`
+	TagBase
+	Base   TagBase `yaml:",inline" bson:",inline" custom:",inline"`
+	Name   string  `yaml:"name" bson:"name" xml:"info>name" toml:"name" msgpack:"n" custom:"label"`
+	Hidden string  `yaml:"-" bson:"-" xml:"-" toml:"-" msgpack:"-" custom:"-"`
+	Body   string  `xml:",chardata"`
+	Items  []struct {
+		Value int `yaml:"val" xml:"urn:x value" msgpack:"v"`
+	}
+}
+
+func TestPathTranslateTag(t *testing.T) {
+	typ := reflect.TypeOf(TagStruct{})
+
+	translateTag(t, typ, "yaml", "id", "Base.ID")
+	translateTag(t, typ, "yaml", "kind", "Base.Kind")
+	translateTag(t, typ, "yaml", "tagbase.id", "TagBase.ID")
+	translateTag(t, typ, "yaml", "items.0.val", "Items.0.Value")
+	translateTagFail(t, typ, "yaml", "Hidden")
+	translateTagFail(t, typ, "yaml", "hidden")
+
+	translateTag(t, typ, "bson", "_id", "Base.ID")
+	translateTag(t, typ, "bson", "kind", "Base.Kind")
+	translateTag(t, typ, "bson", "items.0.value", "Items.0.Value")
+
+	translateTag(t, typ, "xml", "info.name", "Name")
+	translateTag(t, typ, "xml", "Base.id", "Base.ID")
+	translateTag(t, typ, "xml", "Base.meta.kind", "Base.Kind")
+	translateTag(t, typ, "xml", "Items.0.value", "Items.0.Value")
+	translateTag(t, typ, "xml", "id", "TagBase.ID")
+	translateTag(t, typ, "xml", "meta.kind", "TagBase.Kind")
+	translateTagFail(t, typ, "xml", "Body")
+	translateTagFail(t, typ, "xml", "info")
+	translateTagFail(t, typ, "xml", "root")
+	translateTagFail(t, typ, "xml", "XMLName")
+
+	if _, ok := TagAliases(typ, "xml")["root"]; ok {
+		t.Errorf("FAIL(xml): root -> XMLName aliased")
+	}
+
+	translateTag(t, typ, "toml", "name", "Name")
+	translateTag(t, typ, "toml", "kind", "TagBase.Kind")
+	translateTag(t, typ, "toml", "Base.ID", "Base.ID")
+
+	translateTag(t, typ, "msgpack", "n", "Name")
+	translateTag(t, typ, "msgpack", "Items.0.v", "Items.0.Value")
+
+	// Both the embedded and the inlined struct provide ident at the same depth.
+	translateTagFail(t, typ, "custom", "ident")
+	translateTag(t, typ, "custom", "label", "Name")
+	translateTagFail(t, typ, "custom", "Hidden")
+
+	aliases := TagAliases(typ, "yaml")
+	translate(t, aliases, "name.val", "Name.Value")
+	translate(t, aliases, "kind", "Kind")
+}
+
+func translateTag(t *testing.T, typ reflect.Type, tag, old, exp string) {
+	path, err := New(old).TranslateTag(typ, tag)
+	if err != nil {
+		t.Errorf("FAIL(%s): %s -> %s", tag, old, err)
+	} else if path.String() != exp {
+		t.Errorf("FAIL(%s): %s -> %s != %s ", tag, old, path, exp)
+	}
+}
+
+func translateTagFail(t *testing.T, typ reflect.Type, tag, old string) {
+	if path, err := New(old).TranslateTag(typ, tag); err == nil {
+		t.Errorf("FAIL(%s): %s -> %s expected error", tag, old, path)
+	}
+}
+
+type jsonInner struct {
+	A int
+	B int `json:"b"`
+	C int
+	X int
+	Y int
+}
+
+type JSONPtr struct {
+	P int `json:"p"`
+	X int
+	Y int `json:"Y"`
+}
+
+type JSONOuter struct {
+	jsonInner
+	*JSONPtr
+
+	C     int            `json:"-"`
+	D     int            `json:"-,"`
+	Map   map[string]int `json:"map"`
+	lower int
+}
+
+func TestPathTranslateJSONEncoding(t *testing.T) {
+	obj := &JSONOuter{
+		jsonInner: jsonInner{1, 2, 3, 4, 5},
+		JSONPtr:   &JSONPtr{6, 7, 8},
+		C:         9,
+		D:         10,
+		Map:       map[string]int{"k": 12},
+		lower:     13,
+	}
+
+	body, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if exp := "[- A C Y b map p]"; fmt.Sprint(keys) != exp {
+		t.Errorf("FAIL: unexpected JSON keys %s != %s", keys, exp)
+	}
+
+	typ := reflect.TypeOf(obj)
+
+	for _, key := range keys {
+		path, err := P{key}.TranslateJSON(typ)
+		if err != nil {
+			t.Errorf("FAIL: %s -> %s", key, err)
+			continue
+		}
+
+		value, err := path.Get(obj)
+		if err != nil {
+			t.Errorf("FAIL: %s -> %s -> %s", key, path, err)
+		} else if exp := doc[key]; fmt.Sprint(value) != fmt.Sprint(exp) {
+			t.Errorf("FAIL: %s -> %s -> %v != %v", key, path, value, exp)
+		}
+	}
+
+	translateJSON(t, typ, "C", "jsonInner.C")
+	translateJSON(t, typ, "-", "D")
+	translateJSON(t, typ, "Y", "JSONPtr.Y")
+	translateJSON(t, typ, "map.k", "Map.k")
+	translateJSONFail(t, typ, "X")
+	translateJSONFail(t, typ, "lower")
+	translateJSONFail(t, typ, "jsonInner")
+	translateJSONFail(t, typ, "JSONPtr")
+
+	aliases := JSONAliases(typ)
+	translate(t, aliases, "b.p.-", "B.P.D")
+}
+
+func translateJSON(t *testing.T, typ reflect.Type, old, exp string) {
+	path, err := New(old).TranslateJSON(typ)
+	if err != nil {
+		t.Errorf("FAIL: %s -> %s", old, err)
+	} else if path.String() != exp {
+		t.Errorf("FAIL: %s -> %s != %s ", old, path, exp)
+	}
+}
+
+func translateJSONFail(t *testing.T, typ reflect.Type, old string) {
+	if path, err := New(old).TranslateJSON(typ); err == nil {
+		t.Errorf("FAIL: %s -> %s expected error", old, path)
+	}
+}
+
 func translate(t *testing.T, aliases map[string]string, old, exp string) {
 	path := New(old).Translate(aliases).String()
 	if path != exp {
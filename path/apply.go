@@ -3,9 +3,12 @@
 package path
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Apply applies the given context to the object using the path.
@@ -13,8 +16,22 @@ func (path P) Apply(obj interface{}, ctx *Context) (err error) {
 	return apply(reflect.ValueOf(obj), P{}, path, ctx)
 }
 
+// ApplyContext applies the given context to the object using the path and
+// aborts the traversal with the error of done if it is cancelled or if its
+// deadline expires. Blocking channel reads are also interrupted.
+func (path P) ApplyContext(done context.Context, obj interface{}, ctx *Context) error {
+	defer func(prev context.Context) { ctx.done = prev }(ctx.done)
+
+	ctx.done = done
+	return path.Apply(obj, ctx)
+}
+
 func apply(obj reflect.Value, head, tail P, ctx *Context) (err error) {
-	if err := ensure(head, tail, obj, ctx); err != nil {
+	if err := ctx.err(); err != nil {
+		return err
+	}
+
+	if obj, err = ensure(head, tail, obj, ctx); err != nil {
 		return err
 	}
 
@@ -25,6 +42,9 @@ func apply(obj reflect.Value, head, tail P, ctx *Context) (err error) {
 		cont, err = ctx.Fn(head, ctx)
 		ctx.stop = !cont
 
+	} else if getter, ok := implements(obj, pathGetterType).(PathGetter); ok {
+		err = applyToGetter(obj, getter, head, tail[0], tail[1:], ctx)
+
 	} else {
 		switch obj.Kind() {
 
@@ -57,24 +77,41 @@ func apply(obj reflect.Value, head, tail P, ctx *Context) (err error) {
 }
 
 func applyToPtr(obj reflect.Value, head, tail P, ctx *Context) error {
+	name, args, call := splitCall(tail[0])
+	if !call {
+		name = tail[0]
+	}
 
-	if result := obj.MethodByName(tail[0]); result.Kind() != reflect.Invalid {
-		return apply(result, append(head, tail[0]), tail[1:], ctx)
+	if method := methodByName(obj, name); method.Kind() != reflect.Invalid {
+		if call {
+			return applyCall(method, head, tail[0], args, tail[1:], ctx)
+		}
+		return apply(method, append(head, tail[0]), tail[1:], ctx)
 	}
 
 	return apply(obj.Elem(), head, tail, ctx)
 }
 
 func applyToFunc(obj reflect.Value, head P, mid string, tail P, ctx *Context) error {
-	if mid != "()" {
+	name, args, ok := splitCall(mid)
+	if !ok || name != "" {
 		return fmt.Errorf("missing required '()' pathing component at '%s'", head)
 	}
 
-	if !isGetter(obj) {
+	return applyCall(obj, head, mid, args, tail, ctx)
+}
+
+func applyCall(fn reflect.Value, head P, mid, args string, tail P, ctx *Context) error {
+	if !isGetter(fn) {
 		return fmt.Errorf("invalid return signature for function '%s' at '%s'", mid, head)
 	}
 
-	result, err := callGetter(obj)
+	in, err := parseArgs(fn.Type(), args)
+	if err != nil {
+		return fmt.Errorf("invalid arguments for function '%s' at '%s' -> %s", mid, head, err)
+	}
+
+	result, err := callGetter(fn, in...)
 
 	if err != nil {
 		return err
@@ -84,11 +121,28 @@ func applyToFunc(obj reflect.Value, head P, mid string, tail P, ctx *Context) er
 }
 
 func applyToStruct(obj reflect.Value, head P, mid string, tail P, ctx *Context) error {
+	if name, args, ok := splitCall(mid); ok && name != "" {
+		method := methodByName(obj, name)
+		if method.Kind() == reflect.Invalid {
+			return fmt.Errorf("no method '%s' in type '%s' at '%s'", name, obj.Type(), head)
+		}
+
+		return applyCall(method, head, mid, args, tail, ctx)
+	}
+
 	if mid != "*" {
-		result := obj.FieldByName(mid)
+		mid, err := matchFieldName(obj.Type(), head, mid, ctx.Match)
+		if err != nil {
+			return err
+		}
+
+		result, err := fieldByName(obj, head, mid, ctx)
+		if err != nil {
+			return err
+		}
 
 		if result.Kind() == reflect.Invalid {
-			result = obj.MethodByName(mid)
+			result = methodByName(obj, mid)
 		}
 
 		if result.Kind() == reflect.Invalid {
@@ -166,32 +220,97 @@ func applyToChan(obj reflect.Value, head P, mid string, tail P, ctx *Context) er
 		return fmt.Errorf("invalid channel direction '%s' at '%s'", dir, head)
 	}
 
-	switch mid {
+	switch {
 
-	case "1":
-		result, ok := obj.Recv()
-		if !ok {
-			return ErrMissing
+	case mid == "*":
+		return applyToChanRecv(obj, head, mid, tail, -1, nil, ctx)
+
+	case mid == "?":
+		return applyToChanDrain(obj, head, mid, tail, ctx)
+
+	case strings.HasPrefix(mid, "@"):
+		timeout, err := time.ParseDuration(mid[1:])
+		if err != nil {
+			return fmt.Errorf("invalid channel timeout '%s' at '%s' -> %s", mid, head, err)
 		}
 
-		return apply(result, append(head, mid), tail, ctx)
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		return applyToChanRecv(obj, head, mid, tail, -1, timer.C, ctx)
+
+	default:
+		count, err := strconv.ParseInt(mid, 10, 32)
+		if err != nil || count < 0 {
+			return fmt.Errorf("invalid channel component '%s' at '%s'", mid, head)
+		}
+
+		return applyToChanRecv(obj, head, mid, tail, int(count), nil, ctx)
+
+	}
+}
+
+// isChanComponent returns true if the component can be used to read from a
+// channel.
+func isChanComponent(mid string) bool {
+	switch {
+
+	case mid == "*" || mid == "?":
+		return true
+
+	case strings.HasPrefix(mid, "@"):
+		_, err := time.ParseDuration(mid[1:])
+		return err == nil
 
-	case "*":
-		for !ctx.stop {
-			result, ok := obj.Recv()
-			if !ok {
+	default:
+		count, err := strconv.ParseInt(mid, 10, 32)
+		return err == nil && count >= 0
+	}
+}
+
+// applyToChanRecv reads count values from the channel or all the values until
+// the channel is closed if count is negative. Reading is also interrupted when
+// expire fires.
+func applyToChanRecv(
+	obj reflect.Value, head P, mid string, tail P,
+	count int, expire <-chan time.Time, ctx *Context) error {
+
+	for i := 0; (count < 0 || i < count) && !ctx.stop; i++ {
+		result, ok, err := ctx.recv(obj, expire)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			if count < 0 || i > 0 {
 				return nil
 			}
+			return ErrMissing
+		}
 
-			if err := apply(result, append(head, mid), tail, ctx); err != nil && err != ErrMissing {
-				return err
-			}
+		// Reading a single value behaves like an index and therefore doesn't
+		// swallow missing errors the way wildcards do.
+		if err := apply(result, append(head, mid), tail, ctx); err != nil && (err != ErrMissing || count == 1) {
+			return err
 		}
+	}
 
-		return nil
+	return nil
+}
 
-	default:
-		return fmt.Errorf("invalid channel component '%s' at '%s'", mid, head)
+// applyToChanDrain reads all the values that are currently buffered in the
+// channel without blocking.
+func applyToChanDrain(obj reflect.Value, head P, mid string, tail P, ctx *Context) error {
+	for !ctx.stop {
+		result, ok := obj.TryRecv()
+		if !ok {
+			return nil
+		}
 
+		if err := apply(result, append(head, mid), tail, ctx); err != nil && err != ErrMissing {
+			return err
+		}
 	}
+
+	return nil
 }
@@ -0,0 +1,184 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+)
+
+// Add adds delta to the first numeric value in the given object that matches
+// the path. The value can be of any integer, unsigned or float kind and delta
+// of any numeric kind with the exception of floats which can't be added to
+// integers. Missing values are treated as zero. Returns ErrOverflow if the
+// result can't be represented by the type of the value and ErrInvalidType if
+// the value or delta are not numeric.
+func (path P) Add(obj, delta interface{}) error {
+	return path.Update(obj, addOp.fn(delta))
+}
+
+// AddAll adds delta to all the numeric values in the given object that matches
+// the path.
+func (path P) AddAll(obj, delta interface{}) error {
+	return path.UpdateAll(obj, addOp.fn(delta))
+}
+
+// Mul multiplies the first numeric value in the given object that matches the
+// path by factor. Types and errors are handled as in P.Add.
+func (path P) Mul(obj, factor interface{}) error {
+	return path.Update(obj, mulOp.fn(factor))
+}
+
+// Min replaces the first numeric value in the given object that matches the
+// path by the given value if it is smaller. Types and errors are handled as in
+// P.Add.
+func (path P) Min(obj, value interface{}) error {
+	return path.Update(obj, minOp.fn(value))
+}
+
+// Max replaces the first numeric value in the given object that matches the
+// path by the given value if it is larger. Types and errors are handled as in
+// P.Add.
+func (path P) Max(obj, value interface{}) error {
+	return path.Update(obj, maxOp.fn(value))
+}
+
+// arith is an arithmetic operation defined over integers, which are computed
+// with arbitrary precision to detect overflows, and over floats.
+type arith struct {
+	ints   func(a, b *big.Int) *big.Int
+	floats func(a, b float64) float64
+}
+
+var addOp = arith{
+	ints:   func(a, b *big.Int) *big.Int { return new(big.Int).Add(a, b) },
+	floats: func(a, b float64) float64 { return a + b },
+}
+
+var mulOp = arith{
+	ints:   func(a, b *big.Int) *big.Int { return new(big.Int).Mul(a, b) },
+	floats: func(a, b float64) float64 { return a * b },
+}
+
+var minOp = arith{
+	ints: func(a, b *big.Int) *big.Int {
+		if a.Cmp(b) <= 0 {
+			return a
+		}
+		return b
+	},
+	floats: math.Min,
+}
+
+var maxOp = arith{
+	ints: func(a, b *big.Int) *big.Int {
+		if a.Cmp(b) >= 0 {
+			return a
+		}
+		return b
+	},
+	floats: math.Max,
+}
+
+// fn returns an update function which applies the operation to the current
+// value and the given operand.
+func (op arith) fn(operand interface{}) UpdateFn {
+	return func(old interface{}) (interface{}, error) {
+		return op.apply(old, operand)
+	}
+}
+
+// apply computes the operation and returns a result of the same type as old. A
+// nil old value, as held by an empty interface, is treated as the zero value of
+// the type of the operand.
+func (op arith) apply(old, operand interface{}) (interface{}, error) {
+	if operand == nil {
+		return nil, ErrInvalidType
+	}
+
+	a, b := reflect.ValueOf(old), reflect.ValueOf(operand)
+	if old == nil {
+		a = reflect.Zero(b.Type())
+	}
+
+	result := reflect.New(a.Type()).Elem()
+
+	switch a.Kind() {
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		y, ok := bigInt(b)
+		if !ok {
+			return nil, ErrInvalidType
+		}
+
+		r := op.ints(big.NewInt(a.Int()), y)
+		if !r.IsInt64() || result.OverflowInt(r.Int64()) {
+			return nil, ErrOverflow
+		}
+		result.SetInt(r.Int64())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		y, ok := bigInt(b)
+		if !ok {
+			return nil, ErrInvalidType
+		}
+
+		r := op.ints(new(big.Int).SetUint64(a.Uint()), y)
+		if !r.IsUint64() || result.OverflowUint(r.Uint64()) {
+			return nil, ErrOverflow
+		}
+		result.SetUint(r.Uint64())
+
+	case reflect.Float32, reflect.Float64:
+		y, ok := float(b)
+		if !ok {
+			return nil, ErrInvalidType
+		}
+
+		x := a.Float()
+		r := op.floats(x, y)
+		if !math.IsInf(x, 0) && !math.IsInf(y, 0) && (math.IsInf(r, 0) || result.OverflowFloat(r)) {
+			return nil, ErrOverflow
+		}
+		result.SetFloat(r)
+
+	default:
+		return nil, ErrInvalidType
+	}
+
+	return result.Interface(), nil
+}
+
+// bigInt returns the value of an integer or unsigned kind.
+func bigInt(value reflect.Value) (*big.Int, bool) {
+	switch value.Kind() {
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(value.Int()), true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Int).SetUint64(value.Uint()), true
+
+	default:
+		return nil, false
+	}
+}
+
+// float returns the value of a numeric kind as a float.
+func float(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+
+	default:
+		return 0, false
+	}
+}
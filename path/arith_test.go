@@ -0,0 +1,88 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestArith(t *testing.T) {
+	type Count int16
+
+	type Stats struct {
+		I   int
+		I8  int8
+		C   Count
+		U   uint
+		U8  uint8
+		F   float64
+		F32 float32
+	}
+
+	obj := &struct {
+		S Stats
+		M map[string]*Stats
+		V map[string]interface{}
+	}{
+		M: map[string]*Stats{"a": {I: 1}, "b": {I: 2}},
+		V: map[string]interface{}{"x": 1.5},
+	}
+
+	checkArith(t, "S.I", obj, P.Add, 2, 2)
+	checkArith(t, "S.I", obj, P.Add, uint8(3), 5)
+	checkArith(t, "S.I", obj, P.Mul, -2, -10)
+	checkArith(t, "S.I", obj, P.Min, -20, -20)
+	checkArith(t, "S.I", obj, P.Max, 5, 5)
+	checkArith(t, "S.I", obj, P.Min, 10, 5)
+	checkArith(t, "S.C", obj, P.Add, 3, Count(3))
+	checkArith(t, "S.I8", obj, P.Add, 127, int8(127))
+	checkArith(t, "S.U", obj, P.Add, 2, uint(2))
+	checkArith(t, "S.U", obj, P.Add, -1, uint(1))
+	checkArith(t, "S.U8", obj, P.Max, 255, uint8(255))
+	checkArith(t, "S.F", obj, P.Add, 1, 1.0)
+	checkArith(t, "S.F", obj, P.Mul, 2.5, 2.5)
+	checkArith(t, "S.F32", obj, P.Add, 0.5, float32(0.5))
+	checkArith(t, "M.c.I", obj, P.Add, 1, 1)
+	checkArith(t, "V.x", obj, P.Add, 1, 2.5)
+	checkArith(t, "V.y", obj, P.Add, 1, 1)
+
+	checkArithFail(t, "S.I8", obj, P.Add, 1, ErrOverflow)
+	checkArithFail(t, "S.I8", obj, P.Mul, -2, ErrOverflow)
+	checkArithFail(t, "S.U", obj, P.Add, -2, ErrOverflow)
+	checkArithFail(t, "S.U8", obj, P.Add, 1, ErrOverflow)
+	checkArithFail(t, "S.U8", obj, P.Max, 256, ErrOverflow)
+	checkArithFail(t, "S.I", obj, P.Add, uint64(math.MaxUint64), ErrOverflow)
+	checkArithFail(t, "S.F", obj, P.Mul, math.MaxFloat64, ErrOverflow)
+	checkArithFail(t, "S.F32", obj, P.Add, math.MaxFloat64, ErrOverflow)
+	checkArithFail(t, "S.I", obj, P.Add, 1.5, ErrInvalidType)
+	checkArithFail(t, "S.I", obj, P.Add, "1", ErrInvalidType)
+	checkArithFail(t, "S", obj, P.Add, 1, ErrInvalidType)
+
+	if err := New("M.*.I").AddAll(obj, 10); err != nil {
+		t.Errorf("FAIL: AddAll(M.*.I) -> %s", err)
+	}
+
+	getAllInt(t, "AddAll", "M.*.I", obj, []int{11, 12, 11})
+}
+
+func checkArith(t *testing.T, path string, obj interface{}, op func(P, interface{}, interface{}) error, operand, exp interface{}) {
+	if err := op(New(path), obj, operand); err != nil {
+		t.Errorf("FAIL(%s): %v -> %s", path, operand, err)
+		return
+	}
+
+	if value, err := New(path).Get(obj); err != nil {
+		t.Errorf("FAIL(%s): get -> %s", path, err)
+
+	} else if !reflect.DeepEqual(value, exp) {
+		t.Errorf("FAIL(%s): %v -> %v != %v", path, operand, value, exp)
+	}
+}
+
+func checkArithFail(t *testing.T, path string, obj interface{}, op func(P, interface{}, interface{}) error, operand interface{}, exp error) {
+	if err := op(New(path), obj, operand); err != exp {
+		t.Errorf("FAIL(%s): %v -> %v != %v", path, operand, err, exp)
+	}
+}
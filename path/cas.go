@@ -0,0 +1,135 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+var atomicValueType = reflect.TypeOf((*atomic.Value)(nil))
+
+// CompareAndSet sets the first value in the given object that matches the path
+// to value if it is deeply equal to expected and returns whether the value was
+// set. Values that provide a CompareAndSwap method, such as the types of the
+// sync/atomic package, are instead swapped atomically using that method. A nil
+// expected value matches the zero value. Returns ErrMissing if the path doesn't
+// exist and ErrInvalidType if the values can't be given to CompareAndSwap, such
+// as a nil or inconsistently typed value for an atomic.Value.
+//
+// Other values are compared and set within a single traversal without any
+// locking of their own which means that the operation is only atomic if all
+// the accesses to the object are guarded by a common lock. CompareAndSetLocked
+// can be used to hold such a lock for the duration of the operation.
+func (path P) CompareAndSet(obj, expected, value interface{}) (swapped bool, err error) {
+	fn := func(p P, ctx *Context) (bool, error) {
+		swapped, err = compareAndSet(p, ctx, expected, value)
+		return false, err
+	}
+
+	if err = path.Apply(obj, &Context{Fn: fn}); err != nil {
+		swapped = false
+	}
+
+	return
+}
+
+// CompareAndSetLocked is similar to CompareAndSet but holds the given lock
+// while the value is compared and set.
+func (path P) CompareAndSetLocked(lock sync.Locker, obj, expected, value interface{}) (bool, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	return path.CompareAndSet(obj, expected, value)
+}
+
+func compareAndSet(path P, ctx *Context, expected, value interface{}) (bool, error) {
+	obj := ctx.Value()
+
+	if method := methodByName(obj, "CompareAndSwap"); isCompareAndSwap(method) {
+		typ := method.Type()
+
+		old, next := convertTo(expected, typ.In(0)), convertTo(value, typ.In(1))
+		if !old.Type().AssignableTo(typ.In(0)) || !next.Type().AssignableTo(typ.In(1)) {
+			return false, ErrInvalidType
+		}
+
+		if !canSwapAtomicValue(obj, old, next) {
+			return false, ErrInvalidType
+		}
+
+		swapped, err := callGetter(method, old, next)
+		if err != nil {
+			return false, err
+		}
+
+		return swapped.Bool(), nil
+	}
+
+	if !reflect.DeepEqual(interfaceOf(obj), interfaceOf(convertTo(expected, obj.Type()))) {
+		return false, nil
+	}
+
+	if err := set(path, ctx, convertTo(value, obj.Type())); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// isCompareAndSwap returns true if the method has the signature of the
+// CompareAndSwap methods of the sync/atomic types.
+func isCompareAndSwap(method reflect.Value) bool {
+	if method.Kind() != reflect.Func {
+		return false
+	}
+
+	typ := method.Type()
+	return typ.NumIn() == 2 && typ.NumOut() == 1 && typ.Out(0).Kind() == reflect.Bool
+}
+
+// canSwapAtomicValue returns false if the object is an atomic.Value whose
+// CompareAndSwap method would panic given the old and new values which happens
+// if the new value is nil, isn't comparable or if its type differs from the
+// type of the old or of the stored value.
+func canSwapAtomicValue(obj, old, next reflect.Value) bool {
+	for obj.Kind() == reflect.Interface && !obj.IsNil() {
+		obj = obj.Elem()
+	}
+
+	if obj.Kind() == reflect.Struct && obj.CanAddr() {
+		obj = obj.Addr()
+	}
+
+	if obj.Type() != atomicValueType {
+		return true
+	}
+
+	if next.IsNil() || !next.Elem().Type().Comparable() {
+		return false
+	}
+
+	typ := next.Elem().Type()
+	if !old.IsNil() && old.Elem().Type() != typ {
+		return false
+	}
+
+	stored := obj.MethodByName("Load").Call(nil)[0]
+	return stored.IsNil() || stored.Elem().Type() == typ
+}
+
+// convertTo returns the value converted to the given type if possible. A nil
+// value is converted to the zero value of the type.
+func convertTo(value interface{}, typ reflect.Type) reflect.Value {
+	if value == nil {
+		return reflect.Zero(typ)
+	}
+
+	result := reflect.ValueOf(value)
+	if result.Type().ConvertibleTo(typ) {
+		result = result.Convert(typ)
+	}
+
+	return result
+}
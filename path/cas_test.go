@@ -0,0 +1,134 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCompareAndSet(t *testing.T) {
+	type Config struct {
+		Name string
+		Tags []string
+	}
+
+	obj := &struct {
+		I  int
+		S  string
+		C  Config
+		P  *Config
+		M  map[string]int
+		A  atomic.Int64
+		AP *atomic.Uint32
+		AV atomic.Value
+	}{
+		I:  1,
+		S:  "a",
+		C:  Config{Name: "x", Tags: []string{"t"}},
+		M:  map[string]int{"x": 1},
+		AP: new(atomic.Uint32),
+	}
+
+	checkCAS(t, "I", obj, 2, 3, false)
+	checkCAS(t, "I", obj, 1, 3, true)
+	getInt(t, "CAS", "I", obj, 3)
+	checkCAS(t, "I", obj, int64(3), int8(4), true)
+	getInt(t, "CAS", "I", obj, 4)
+	checkCAS(t, "S", obj, "a", "b", true)
+	checkCAS(t, "C", obj, Config{Name: "x"}, Config{}, false)
+	checkCAS(t, "C", obj, Config{Name: "x", Tags: []string{"t"}}, Config{Name: "y"}, true)
+	checkCAS(t, "P", obj, nil, &Config{Name: "p"}, true)
+	checkCAS(t, "P", obj, nil, &Config{}, false)
+	checkCAS(t, "P", obj, &Config{Name: "p"}, nil, true)
+	checkCAS(t, "M.x", obj, 1, 2, true)
+	getInt(t, "CAS", "M.x", obj, 2)
+
+	checkCAS(t, "A", obj, 1, 2, false)
+	checkCAS(t, "A", obj, 0, 2, true)
+	checkCAS(t, "AP", obj, 0, 3, true)
+	checkCAS(t, "AV", obj, nil, "a", true)
+	checkCAS(t, "AV", obj, "a", "b", true)
+
+	if value := obj.A.Load(); value != 2 {
+		t.Errorf("FAIL: A -> %d != 2", value)
+	}
+
+	if value := obj.AP.Load(); value != 3 {
+		t.Errorf("FAIL: AP -> %d != 3", value)
+	}
+
+	if value := obj.AV.Load(); value != "b" {
+		t.Errorf("FAIL: AV -> %v != b", value)
+	}
+
+	if _, err := New("M.y").CompareAndSet(obj, 0, 1); err != ErrMissing {
+		t.Errorf("FAIL: CompareAndSet(M.y) -> %v != %v", err, ErrMissing)
+	}
+
+	if _, err := New("I").CompareAndSet(obj, 4, "str"); err != ErrInvalidType {
+		t.Errorf("FAIL: CompareAndSet(I) -> %v != %v", err, ErrInvalidType)
+	}
+
+	if _, err := New("A").CompareAndSet(obj, 2, "str"); err != ErrInvalidType {
+		t.Errorf("FAIL: CompareAndSet(A) -> %v != %v", err, ErrInvalidType)
+	}
+
+	// Values that would make atomic.Value panic are rejected.
+	for _, values := range [][2]interface{}{{nil, nil}, {"b", nil}, {"b", 1}, {1, 1}, {"b", []int{1}}} {
+		if _, err := New("AV").CompareAndSet(obj, values[0], values[1]); err != ErrInvalidType {
+			t.Errorf("FAIL: CompareAndSet(AV, %v, %v) -> %v != %v", values[0], values[1], err, ErrInvalidType)
+		}
+	}
+
+	if value := obj.AV.Load(); value != "b" {
+		t.Errorf("FAIL: AV -> %v != b", value)
+	}
+}
+
+func TestCompareAndSetLocked(t *testing.T) {
+	obj := map[string]map[string]int{"a": {"count": 0}}
+
+	var lock sync.Mutex
+	var group sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		group.Add(1)
+
+		go func() {
+			defer group.Done()
+
+			for n := 0; n < 100; {
+				lock.Lock()
+				value, _ := New("a.count").Get(obj)
+				lock.Unlock()
+
+				swapped, err := New("a.count").CompareAndSetLocked(&lock, obj, value, value.(int)+1)
+				if err != nil {
+					t.Errorf("FAIL: CompareAndSetLocked -> %s", err)
+					return
+				}
+
+				if swapped {
+					n++
+				}
+			}
+		}()
+	}
+
+	group.Wait()
+
+	if value := obj["a"]["count"]; value != 1000 {
+		t.Errorf("FAIL: count -> %d != 1000", value)
+	}
+}
+
+func checkCAS(t *testing.T, path string, obj, expected, value interface{}, exp bool) {
+	if swapped, err := New(path).CompareAndSet(obj, expected, value); err != nil {
+		t.Errorf("FAIL(%s): CompareAndSet(%v, %v) -> %s", path, expected, value, err)
+
+	} else if swapped != exp {
+		t.Errorf("FAIL(%s): CompareAndSet(%v, %v) -> %t != %t", path, expected, value, swapped, exp)
+	}
+}
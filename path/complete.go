@@ -0,0 +1,223 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Completion is a candidate component to complete a partial path.
+type Completion struct {
+
+	// Component is the path component that completes the partial component.
+	// Methods that require arguments end with an opening parenthesis which
+	// must be completed with the arguments of the call.
+	Component string
+
+	// Type is the type of the value reached by the component or nil if it
+	// can't be determined.
+	Type reflect.Type
+}
+
+// Completions contains the candidate components of a partial path.
+type Completions struct {
+
+	// Path is the path that was resolved before the partial component.
+	Path P
+
+	// Items are the candidate components sorted by name.
+	Items []Completion
+
+	// Len is the number of valid indexes if the resolved path leads to a
+	// slice or an array and -1 otherwise. The length of slices is only known
+	// when completing against a value.
+	Len int
+}
+
+// Complete returns the candidate components that complete the last component
+// of the partial path when applied to the given object. All the components of
+// the partial path except the last must resolve in the object. Candidates are
+// the struct fields and methods that start with the last component, the keys
+// of maps and the indexes of slices present in the object as well as the
+// wildcard component. Returns ErrNil if the object is nil.
+func Complete(obj interface{}, partial string) (result Completions, err error) {
+	path := New(partial)
+	head, mid := path[:len(path)-1], path.Last()
+
+	var value reflect.Value
+	fn := func(_ P, ctx *Context) (bool, error) {
+		value = ctx.Value()
+		return false, nil
+	}
+
+	if err = head.Apply(obj, &Context{Fn: fn}); err != nil {
+		return
+	}
+
+	if !value.IsValid() {
+		err = ErrNil
+		return
+	}
+
+	result = Completions{Path: head, Len: -1}
+
+	// Values that are nil don't have anything to list so we fallback to
+	// their type.
+	for value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if _, ok := implements(value, pathGetterType).(PathGetter); ok {
+		result.Items = completeGetter(value, mid)
+		return
+	}
+
+	result.Items = completeType(value.Type(), value.CanAddr(), mid)
+
+	if value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+
+	case reflect.Array, reflect.Slice:
+		result.Len = value.Len()
+		if index, err := strconv.Atoi(mid); err == nil && index >= 0 && index < value.Len() {
+			result.Items = append(result.Items, completion(mid, value.Index(index)))
+		}
+
+	case reflect.Map:
+		if value.Type().Key().Kind() == reflect.String {
+			for _, key := range value.MapKeys() {
+				if strings.HasPrefix(key.String(), mid) {
+					result.Items = append(result.Items, completion(key.String(), value.MapIndex(key)))
+				}
+			}
+		}
+	}
+
+	sortCompletions(result.Items)
+	return
+}
+
+// CompleteType is similar to Complete but only relies on the given type which
+// means that map keys and slice indexes can't be listed. The partial path
+// without its last component must be resolvable by TypeOf.
+func CompleteType(typ reflect.Type, partial string) (result Completions, err error) {
+	path := New(partial)
+	head, mid := path[:len(path)-1], path.Last()
+
+	if typ, err = TypeOf(typ, head); err != nil {
+		return
+	}
+
+	result = Completions{Path: head, Len: -1, Items: completeType(typ, true, mid)}
+
+	if elem := indirectType(typ); elem.Kind() == reflect.Array {
+		result.Len = elem.Len()
+	}
+
+	sortCompletions(result.Items)
+	return
+}
+
+// completeType returns the candidates that can be determined from the type
+// alone. Pointer methods are only included if the struct is addressable.
+func completeType(typ reflect.Type, addressable bool, mid string) (items []Completion) {
+	if typ.Implements(pathGetterType) || reflect.PtrTo(typ).Implements(pathGetterType) {
+		return
+	}
+
+	methods := typ
+	if typ.Kind() == reflect.Struct && addressable {
+		methods = reflect.PtrTo(typ)
+	}
+
+	for i := 0; i < methods.NumMethod(); i++ {
+		name := methods.Method(i).Name
+		if !strings.HasPrefix(name, mid) {
+			continue
+		}
+
+		method, ok := staticMethod(methods, name)
+		if !ok || !isGetterType(method) {
+			continue
+		}
+
+		if method.NumIn() == 0 {
+			items = append(items, Completion{name + "()", method.Out(0)})
+		} else {
+			items = append(items, Completion{name + "(", method.Out(0)})
+		}
+	}
+
+	typ = indirectType(typ)
+
+	switch typ.Kind() {
+
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if field := typ.Field(i); field.PkgPath == "" && strings.HasPrefix(field.Name, mid) {
+				items = append(items, Completion{field.Name, field.Type})
+			}
+		}
+
+	case reflect.Array, reflect.Slice:
+		if strings.HasPrefix("*", mid) {
+			items = append(items, Completion{"*", typ.Elem()})
+		}
+
+	case reflect.Map:
+		if typ.Key().Kind() == reflect.String && strings.HasPrefix("*", mid) {
+			items = append(items, Completion{"*", typ.Elem()})
+		}
+
+	case reflect.Func:
+		if typ.NumIn() == 0 && isGetterType(typ) && strings.HasPrefix("()", mid) {
+			items = append(items, Completion{"()", typ.Out(0)})
+		}
+	}
+
+	return
+}
+
+func completeGetter(value reflect.Value, mid string) (items []Completion) {
+	lister, ok := implements(value, pathListerType).(PathLister)
+	if !ok {
+		return
+	}
+
+	names, err := lister.PathList()
+	if err != nil {
+		return
+	}
+
+	for _, name := range names {
+		if strings.HasPrefix(name, mid) {
+			items = append(items, Completion{Component: name})
+		}
+	}
+
+	if strings.HasPrefix("*", mid) {
+		items = append(items, Completion{Component: "*"})
+	}
+
+	sortCompletions(items)
+	return
+}
+
+// completion returns the candidate for the given value using its dynamic type
+// if it's held in an interface.
+func completion(name string, value reflect.Value) Completion {
+	if value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+	return Completion{name, value.Type()}
+}
+
+func sortCompletions(items []Completion) {
+	sort.Slice(items, func(i, j int) bool { return items[i].Component < items[j].Component })
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type CompleteUser struct {
+	Name    string
+	Address *struct{ City string }
+	Aliases []string
+	Meta    map[string]interface{}
+}
+
+func (u *CompleteUser) Alias(i int) string { return u.Aliases[i] }
+
+func (u *CompleteUser) AliasCount() int { return len(u.Aliases) }
+
+func TestComplete(t *testing.T) {
+	obj := &struct {
+		Users  []CompleteUser
+		Index  map[string]*CompleteUser
+		Fixed  [3]int
+		Record *Record
+	}{
+		Users: []CompleteUser{
+			{Name: "alice", Aliases: []string{"a", "al"}, Meta: map[string]interface{}{"age": 30, "admin": true}},
+		},
+		Index:  map[string]*CompleteUser{"alice": nil, "bob": nil, "al": nil},
+		Record: &Record{fields: map[string]interface{}{"x": 1, "xy": 2, "z": 3}},
+	}
+
+	checkComplete(t, obj, "", -1, "Fixed:[3]int Index:map[string]*path.CompleteUser Record:*path.Record Users:[]path.CompleteUser")
+	checkComplete(t, obj, "U", -1, "Users:[]path.CompleteUser")
+	checkComplete(t, obj, "Users.", 1, "*:path.CompleteUser")
+	checkComplete(t, obj, "Users.0", 1, "0:path.CompleteUser")
+	checkComplete(t, obj, "Users.1", 1, "")
+	checkComplete(t, obj, "Users.0.A", -1, "Address:*struct { City string } Alias(:string AliasCount():int Aliases:[]string")
+	checkComplete(t, obj, "Users.0.Address.", -1, "City:string")
+	checkComplete(t, obj, "Users.0.Aliases.*", 2, "*:string")
+	checkComplete(t, obj, "Users.0.Meta.a", -1, "admin:bool age:int")
+	checkComplete(t, obj, "Index.al", -1, "al:*path.CompleteUser alice:*path.CompleteUser")
+	checkComplete(t, obj, "Fixed.", 3, "*:int")
+	checkComplete(t, obj, "Record.x", -1, "x:<nil> xy:<nil>")
+
+	if _, err := Complete(obj, "Users.1.Name"); err != ErrMissing {
+		t.Errorf("FAIL: Users.1.Name -> expected Missing got %v", err)
+	}
+
+	if _, err := Complete(nil, ""); err != ErrNil {
+		t.Errorf("FAIL: Complete(nil) -> %v != %v", err, ErrNil)
+	}
+
+	typ := reflect.TypeOf(obj)
+	checkCompleteType(t, typ, "Users.0.Add", -1, "Address:*struct { City string }")
+	checkCompleteType(t, typ, "Users.*.Aliases.", -1, "*:string")
+	checkCompleteType(t, typ, "Index.*.A", -1, "Address:*struct { City string } Alias(:string AliasCount():int Aliases:[]string")
+	checkCompleteType(t, typ, "Fixed.", 3, "*:int")
+	checkCompleteType(t, typ, "Index.x", -1, "")
+
+	if _, err := CompleteType(typ, "Missing.A"); err == nil {
+		t.Errorf("FAIL: Missing.A -> expected error")
+	}
+}
+
+func checkComplete(t *testing.T, obj interface{}, partial string, expLen int, exp string) {
+	result, err := Complete(obj, partial)
+	checkCompletions(t, partial, result, err, expLen, exp)
+}
+
+func checkCompleteType(t *testing.T, typ reflect.Type, partial string, expLen int, exp string) {
+	result, err := CompleteType(typ, partial)
+	checkCompletions(t, partial, result, err, expLen, exp)
+}
+
+func checkCompletions(t *testing.T, partial string, result Completions, err error, expLen int, exp string) {
+	if err != nil {
+		t.Errorf("FAIL: %s -> %s", partial, err)
+		return
+	}
+
+	var items []string
+	for _, item := range result.Items {
+		items = append(items, fmt.Sprintf("%s:%v", item.Component, item.Type))
+	}
+
+	if strings.Join(items, " ") != exp {
+		t.Errorf("FAIL: %s -> %s != %s", partial, strings.Join(items, " "), exp)
+	}
+
+	if result.Len != expLen {
+		t.Errorf("FAIL: %s -> len %d != %d", partial, result.Len, expLen)
+	}
+}
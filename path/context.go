@@ -3,7 +3,9 @@
 package path
 
 import (
+	"context"
 	"reflect"
+	"time"
 )
 
 // Context contains the state of the path crawl.
@@ -24,10 +26,41 @@ type Context struct {
 	// unsafe.Pointers will cause an error to be returned.
 	CreateIfMissing bool
 
-	stop   bool
-	values []reflect.Value
+	// Match controls how path components are matched against the names of
+	// struct fields. Defaults to MatchExact.
+	Match MatchMode
+
+	// DryRun prevents the object from being modified. The modifications that
+	// would have been made, including the values created as a result of
+	// CreateIfMissing, are instead recorded and can be retrieved using the
+	// Changes function.
+	DryRun bool
+
+	stop    bool
+	values  []reflect.Value
+	done    context.Context
+	tx      *Tx
+	changes []Change
 }
 
+// MatchMode indicates how path components are matched against the names of
+// struct fields.
+type MatchMode int
+
+const (
+	// MatchExact requires that path components be identical to the field
+	// names.
+	MatchExact MatchMode = iota
+
+	// MatchFold matches field names without regards to case.
+	MatchFold
+
+	// MatchNormalized matches field names without regards to case and ignores
+	// any '_', '-' or ' ' separators such that 'user_id', 'user-id', 'userId'
+	// and 'USERID' all match the field UserID.
+	MatchNormalized
+)
+
 // Value returns the current value being tracked by the path crawler.
 func (ctx *Context) Value() reflect.Value {
 	return ctx.values[len(ctx.values)-1]
@@ -49,3 +82,42 @@ func (ctx *Context) push(value reflect.Value) {
 func (ctx *Context) pop() {
 	ctx.values = ctx.values[:len(ctx.values)-1]
 }
+
+// err returns the error of the cancellation context if it was cancelled or if
+// its deadline expired.
+func (ctx *Context) err() error {
+	if ctx.done == nil {
+		return nil
+	}
+	return ctx.done.Err()
+}
+
+// recv reads a value from the given channel while also waiting on the
+// cancellation context so that a blocked read can be interrupted. If expire is
+// not nil then the read is also abandoned when it fires in which case ok is
+// false as if the channel had been closed.
+func (ctx *Context) recv(ch reflect.Value, expire <-chan time.Time) (value reflect.Value, ok bool, err error) {
+	if ctx.done == nil && expire == nil {
+		value, ok = ch.Recv()
+		return
+	}
+
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: ch},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(expire)},
+	}
+
+	if ctx.done != nil {
+		cases = append(cases, reflect.SelectCase{
+			Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.done.Done())})
+	}
+
+	switch chosen, value, ok := reflect.Select(cases); chosen {
+	case 0:
+		return value, ok, nil
+	case 1:
+		return reflect.Value{}, false, nil
+	default:
+		return reflect.Value{}, false, ctx.done.Err()
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+)
+
+// copyKey identifies a pointer, map or slice that was already copied. The type
+// is required given that a struct and its first field share the same address.
+type copyKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// deepCopy returns a copy of the value which shares no pointers, maps or slices
+// with the original. Pointers, maps and slices that are reachable multiple
+// times, including cycles, are copied once. Channels, functions and the
+// unexported fields of structs are shared with the original.
+func deepCopy(value reflect.Value) reflect.Value {
+	return copyValue(value, map[copyKey]reflect.Value{})
+}
+
+func copyValue(value reflect.Value, seen map[copyKey]reflect.Value) reflect.Value {
+	switch value.Kind() {
+
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+
+		key := copyKey{value.Pointer(), value.Type()}
+		if result, ok := seen[key]; ok {
+			return result
+		}
+
+		result := reflect.New(value.Type().Elem())
+		seen[key] = result
+		result.Elem().Set(copyValue(value.Elem(), seen))
+		return result
+
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+
+		result := reflect.New(value.Type()).Elem()
+		result.Set(copyValue(value.Elem(), seen))
+		return result
+
+	case reflect.Struct:
+		result := reflect.New(value.Type()).Elem()
+		result.Set(value)
+
+		for i := 0; i < value.NumField(); i++ {
+			if field := result.Field(i); field.CanSet() {
+				field.Set(copyValue(value.Field(i), seen))
+			}
+		}
+		return result
+
+	case reflect.Array:
+		result := reflect.New(value.Type()).Elem()
+
+		for i := 0; i < value.Len(); i++ {
+			result.Index(i).Set(copyValue(value.Index(i), seen))
+		}
+		return result
+
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+
+		// Empty slices can share their address with unrelated values and
+		// slices of different lengths can share the same array.
+		key := copyKey{value.Pointer(), value.Type()}
+		if result, ok := seen[key]; ok && result.Len() == value.Len() {
+			return result
+		}
+
+		result := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		if value.Len() > 0 {
+			seen[key] = result
+		}
+
+		for i := 0; i < value.Len(); i++ {
+			result.Index(i).Set(copyValue(value.Index(i), seen))
+		}
+		return result
+
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+
+		key := copyKey{value.Pointer(), value.Type()}
+		if result, ok := seen[key]; ok {
+			return result
+		}
+
+		result := reflect.MakeMapWithSize(value.Type(), value.Len())
+		seen[key] = result
+
+		for _, key := range value.MapKeys() {
+			result.SetMapIndex(key, copyValue(value.MapIndex(key), seen))
+		}
+		return result
+
+	default:
+		return value
+	}
+}
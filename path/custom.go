@@ -0,0 +1,102 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// PathGetter can be implemented by types that want to control how they are
+// traversed. PathGet is called with the path component to resolve and should
+// return ErrMissing if no value is associated with the component.
+type PathGetter interface {
+	PathGet(component string) (interface{}, error)
+}
+
+// PathSetter can be implemented by types that want to control how their values
+// are modified. PathSet is called with the last component of the path and the
+// value to be written.
+type PathSetter interface {
+	PathSet(component string, value interface{}) error
+}
+
+// PathLister can be implemented by a PathGetter to list the components that
+// should be visited when a wildcard component is encountered.
+type PathLister interface {
+	PathList() ([]string, error)
+}
+
+var (
+	pathGetterType = reflect.TypeOf((*PathGetter)(nil)).Elem()
+	pathSetterType = reflect.TypeOf((*PathSetter)(nil)).Elem()
+	pathListerType = reflect.TypeOf((*PathLister)(nil)).Elem()
+
+	emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+)
+
+// implements returns the object as an interface{} if it or its address
+// implements the given interface type. Returns nil otherwise.
+func implements(obj reflect.Value, typ reflect.Type) interface{} {
+	if obj.Kind() == reflect.Invalid || !obj.CanInterface() {
+		return nil
+	}
+
+	if obj.Type().Implements(typ) {
+		if isNillable(obj) && obj.IsNil() {
+			return nil
+		}
+		return obj.Interface()
+	}
+
+	if obj.CanAddr() && reflect.PtrTo(obj.Type()).Implements(typ) {
+		return obj.Addr().Interface()
+	}
+
+	return nil
+}
+
+func applyToGetter(obj reflect.Value, getter PathGetter, head P, mid string, tail P, ctx *Context) error {
+	if mid == "*" {
+		lister, ok := implements(obj, pathListerType).(PathLister)
+		if !ok {
+			return fmt.Errorf("unable to list components of '%s' at '%s'", obj.Type(), head)
+		}
+
+		items, err := lister.PathList()
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(items) && !ctx.stop; i++ {
+			if err := applyToGetter(obj, getter, head, items[i], tail, ctx); err != nil && err != ErrMissing {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	value, err := getter.PathGet(mid)
+
+	// A missing value can still be created through the PathSetter interface so
+	// we hand off an empty placeholder which will be replaced by set.
+	if err == ErrMissing && ctx.CreateIfMissing && len(tail) == 0 {
+		if _, ok := implements(obj, pathSetterType).(PathSetter); ok {
+			return apply(reflect.New(emptyInterfaceType).Elem(), append(head, mid), tail, ctx)
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		if len(tail) > 0 {
+			return ErrMissing
+		}
+		return apply(reflect.New(emptyInterfaceType).Elem(), append(head, mid), tail, ctx)
+	}
+
+	return apply(reflect.ValueOf(value), append(head, mid), tail, ctx)
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+type Record struct {
+	fields map[string]interface{}
+}
+
+func (r *Record) PathGet(component string) (interface{}, error) {
+	if component == "fail" {
+		return nil, fmt.Errorf("BOOM")
+	}
+
+	value, ok := r.fields[component]
+	if !ok {
+		return nil, ErrMissing
+	}
+	return value, nil
+}
+
+func (r *Record) PathSet(component string, value interface{}) error {
+	if _, ok := value.(int); !ok && component == "count" {
+		return ErrInvalidType
+	}
+
+	r.fields[component] = value
+	return nil
+}
+
+func (r *Record) PathList() ([]string, error) {
+	var keys []string
+	for key := range r.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+type Getter struct{ value int }
+
+func (g Getter) PathGet(component string) (interface{}, error) {
+	return g.value, nil
+}
+
+func TestCustom(t *testing.T) {
+	inner := &Record{fields: map[string]interface{}{"x": 1, "y": 2}}
+	obj := struct {
+		R *Record
+		M map[string]*Record
+		G Getter
+		N *Record
+	}{
+		R: &Record{fields: map[string]interface{}{"a": 10, "b": inner, "c": nil}},
+		M: map[string]*Record{"k": inner},
+		G: Getter{100},
+	}
+
+	getInt(t, "custom", "R.a", &obj, 10)
+	getInt(t, "custom", "R.b.x", &obj, 1)
+	getInt(t, "custom", "M.k.y", &obj, 2)
+	getInt(t, "custom", "G.anything", &obj, 100)
+	getAllInt(t, "custom", "R.b.*", &obj, []int{1, 2})
+	getAllInt(t, "custom", "M.*.*", &obj, []int{1, 2})
+
+	if value, err := New("R.c").Get(&obj); err != nil || value != nil {
+		t.Errorf("FAIL(custom): R.c -> %v, %v", value, err)
+	}
+
+	getMissing(t, "custom", "R.z", &obj)
+	getMissing(t, "custom", "R.c.z", &obj)
+	getMissing(t, "custom", "N.a", &obj)
+	getFail(t, "custom", "R.fail", &obj)
+	getFail(t, "custom", "G.*", &obj)
+
+	setObj(t, "custom", "R.a", &obj, 20)
+	setObj(t, "custom", "R.d", &obj, 30)
+	setObj(t, "custom", "R.b.z", &obj, 3)
+	setObj(t, "custom", "R.count", &obj, 4)
+	setFail(t, "custom", "R.count", &obj, "abc")
+	setFail(t, "custom", "R.e.f", &obj, 5)
+	getAllInt(t, "custom", "R.b.*", &obj, []int{1, 2, 3})
+}
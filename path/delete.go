@@ -0,0 +1,40 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+)
+
+// Delete removes the first value in the given object that matches the path.
+// Map keys are deleted while any other value, including slice elements, is
+// reset to its zero value. Returns ErrMissing if the path could not be
+// completed.
+func (path P) Delete(obj interface{}) error {
+	return path.Apply(obj, deleteContext(false))
+}
+
+// DeleteAll removes all the values in the given object that matches the path.
+// Values are removed as in Delete.
+func (path P) DeleteAll(obj interface{}) error {
+	return path.Apply(obj, deleteContext(true))
+}
+
+// deleteContext returns a context which removes the first match or all the
+// matches if all is true.
+func deleteContext(all bool) *Context {
+	fn := func(p P, ctx *Context) (bool, error) {
+		return all, remove(p, ctx)
+	}
+
+	return &Context{Fn: fn}
+}
+
+func remove(path P, ctx *Context) error {
+	if parent := ctx.Parent(); parent.Kind() == reflect.Map {
+		ctx.setMapIndex(path, parent, reflect.ValueOf(path.Last()), reflect.Value{}, false)
+		return nil
+	}
+
+	return set(path, ctx, reflect.Zero(ctx.Value().Type()))
+}
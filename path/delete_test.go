@@ -0,0 +1,68 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDelete(t *testing.T) {
+	type Item struct {
+		A int
+	}
+
+	obj := &struct {
+		I int
+		P *Item
+		S []int
+		M map[string]int
+		V interface{}
+	}{
+		I: 1,
+		P: &Item{A: 1},
+		S: []int{1, 2, 3},
+		M: map[string]int{"a": 1, "b": 2},
+		V: 1,
+	}
+
+	if err := New("I").Delete(obj); err != nil || obj.I != 0 {
+		t.Errorf("FAIL: Delete(I) -> %d, %v", obj.I, err)
+	}
+
+	if err := New("P").Delete(obj); err != nil || obj.P != nil {
+		t.Errorf("FAIL: Delete(P) -> %v, %v", obj.P, err)
+	}
+
+	if err := New("S.1").Delete(obj); err != nil || !reflect.DeepEqual(obj.S, []int{1, 0, 3}) {
+		t.Errorf("FAIL: Delete(S.1) -> %v, %v", obj.S, err)
+	}
+
+	if err := New("V").Delete(obj); err != nil || obj.V != nil {
+		t.Errorf("FAIL: Delete(V) -> %v, %v", obj.V, err)
+	}
+
+	changes, err := New("M.a").PreviewDelete(obj)
+	if exp := []Change{{Path: New("M.a"), Old: 1}}; err != nil || !reflect.DeepEqual(changes, exp) {
+		t.Errorf("FAIL: PreviewDelete(M.a) -> %+v != %+v, %v", changes, exp, err)
+	}
+
+	if err := New("M.a").Delete(obj); err != nil || !reflect.DeepEqual(obj.M, map[string]int{"b": 2}) {
+		t.Errorf("FAIL: Delete(M.a) -> %v, %v", obj.M, err)
+	}
+
+	if err := New("M.c").Delete(obj); err != ErrMissing {
+		t.Errorf("FAIL: Delete(M.c) -> %v != %v", err, ErrMissing)
+	}
+
+	obj.M = map[string]int{"a": 1, "b": 2}
+
+	changes, err = New("M.*").PreviewDeleteAll(obj)
+	if err != nil || len(changes) != 2 || changes[0].New != nil || obj.M["a"] != 1 {
+		t.Errorf("FAIL: PreviewDeleteAll(M.*) -> %+v, %v", changes, err)
+	}
+
+	if err := New("M.*").DeleteAll(obj); err != nil || len(obj.M) != 0 {
+		t.Errorf("FAIL: DeleteAll(M.*) -> %v, %v", obj.M, err)
+	}
+}
@@ -21,7 +21,11 @@ A wildcard component, denoted by the '*' character, is also available when
 using the GetAll to return all the values that match the path pattern.
 
 For channels a wildcard component can be provided to read all values until the
-channel is closed or a count which to indicate the number of values to read.
+channel is closed or a count which to indicate the number of values to read. The
+'?' component reads only the values currently buffered in the channel without
+blocking and a '@' component followed by a duration (e.g. '@100ms') reads all
+values until the channel is closed or the duration expires. Note that since '.'
+is the path separator, fractional durations must be written using a smaller unit.
 
 Functions contains special handling whereby to path through a function the '()'
 component needs to be provided and the function must take no input arguments and
@@ -29,10 +33,116 @@ output a single value with an optional error. To set, the function must take a
 single input argument and return at most a single error argument. The errors
 returned by function calls will be reported as errors from the pathing function.
 
+Methods and function values can also be called with arguments using components
+such as 'Lookup("alice")' or 'At(3)' for methods and '("alice")' for function
+values. Arguments are string, integer, float or boolean literals separated by
+commas and are converted to the parameter types of the function, including
+variadic parameters. Strings must be quoted.
+
+Methods with a pointer receiver are available on any struct that is
+addressable, such as the elements of a slice, but not on a struct stored by
+value in a map. Fields and methods promoted from embedded structs and
+interfaces can be accessed directly. A nil embedded pointer is created when
+setting a promoted field and is otherwise reported as missing, including when
+calling a method promoted through it.
+
+Struct fields are matched exactly by default. The Match field of Context can be
+used to match fields without regards to case or to also ignore the '_', '-'
+and ' ' separators so that 'user_id' matches the UserID field. An error is
+returned if a component matches multiple fields.
+
+Types can take control of their traversal by implementing the PathGetter
+interface which is used to resolve each component instead of reflection. The
+PathLister interface is used to expand wildcard components and the PathSetter
+interface is used when modifying a value held by such a type.
+
+Values can be modified based on their current value using the P.Update and
+P.UpdateAll functions which read and write each match within a single
+traversal. Values held in maps are written back into their map.
+
+Numeric values of any integer, unsigned or float kind can be modified using
+the P.Add, P.AddAll, P.Mul, P.Min and P.Max functions. Results that can't be
+represented by the type of the value are rejected with ErrOverflow.
+
+Optimistic updates are supported by the P.CompareAndSet function which only
+sets a value if it is deeply equal to an expected value. The types of the
+sync/atomic package are swapped atomically while other values rely on a lock
+held by the caller, either directly or through P.CompareAndSetLocked.
+
+Values are removed using the P.Delete and P.DeleteAll functions which delete
+map keys and reset any other value to its zero value. The Move, Copy and Swap
+functions transfer values between two paths of an object, creating the
+missing components of the destination. Copy makes a deep copy of the value
+while Move and Swap leave the object unmodified if any of their steps fail.
+
+Objects can be layered on top of each other using the Merge function which
+recursively merges structs, maps and pointers. The strategy used to merge the
+values matching a path pattern can be changed to replace them, to append or
+union slices, or to skip the zero values of the source.
+
+A subset of an object can be extracted using the Project function which
+returns a deep copy of the object where only the values selected by a list of
+paths are kept, or using the ProjectMap function which returns the selected
+values in nested maps keyed by the components of their path.
+
+Sensitive values can be scrubbed using the Redact function which returns a
+deep copy of an object where the values matching a list of path patterns, or
+held by fields tagged with `redact:"true"`, are masked. The '**' component
+matches any number of components such that '**.Password' matches a Password
+field at any depth. The mask used for strings is set through RedactOptions.
+
+Modifications can be recorded in a Tx which allows them to be rolled back,
+including map keys that were created and slices that were expanded. Failed
+operations within a Tx are rolled back automatically.
+
+The DryRun field of Context, or the P.PreviewSet, P.PreviewSetAll,
+P.PreviewDelete and P.PreviewDeleteAll functions, can be used to preview a
+modification without making it. The concrete paths that would be written,
+created or deleted along with their old and new values are then reported as a
+list of changes.
+
+Values can be validated using Rules which associate path patterns to a list
+of checks such as 'required', 'min=0' or 'email'. All the values matching a
+pattern are checked and any failures are reported as violations which contain
+the concrete path of the value.
+
+Paths can also be validated against a type before any value exists using the
+TypeOf function which returns the static type reached by a path. Paths that go
+through interfaces can only be resolved with an actual value in which case
+ErrUnresolvable is returned.
+
+The Paths function enumerates all the path patterns of a type along with the
+types they reach, optionally including the getter methods, which is useful to
+document or to suggest the paths that can be used with a type.
+
+Partial paths can be completed using the Complete function which lists the
+candidates for the last component of a path based on a value, including its
+map keys and slice indexes, or using the CompleteType function which only
+relies on a type.
+
+Traversals can be bounded by a context.Context using the ApplyContext,
+GetContext and GetAllContext functions. When the context is cancelled or its
+deadline expires, the traversal is aborted and the error of the context is
+returned. This also interrupts reads on channels that would otherwise block.
+
 A translation mechanism is available to convert JSON paths into paths usable by
 gopath. This is accomplished by creating an alias table using the JSONAliases
 function which is then used to translate paths using the Path.Translate
 function.
 
+Since the alias table is shared by all the structs of a type, JSON names that
+are reused by different structs will collide. The P.TranslateJSON function
+avoids this by resolving each component against the struct found at that
+position in the type and reports an error for unknown names.
+
+Paths using the names of other struct tags can be translated in the same way
+using the TagAliases and P.TranslateTag functions. The naming rules of the
+common yaml, bson, xml, toml and msgpack encoders are followed which includes
+inlined structs, nested xml names and fields skipped with the '-' marker.
+
+The reverse translation, from a gopath path to a path using the names of a
+struct tag, is available through the P.TranslateToJSON and P.TranslateToTag
+functions which is useful when reporting paths back to users.
+
 */
 package path
@@ -0,0 +1,115 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+)
+
+// Change describes a modification made, or that would have been made in dry
+// run mode, to the value located at a concrete path.
+type Change struct {
+
+	// Path is the concrete path of the modified value.
+	Path P
+
+	// Old is the value prior to the modification. It is nil if the value
+	// didn't exist, as is the case for a new map key, or if it can't be read,
+	// as is the case for channels and setter functions.
+	Old interface{}
+
+	// New is the value after the modification. It is nil if the value was
+	// deleted from its map.
+	New interface{}
+
+	// Created indicates that the value was created to fill in a missing
+	// component of the path as a result of CreateIfMissing.
+	Created bool
+}
+
+// Changes returns the modifications recorded by the context in dry run mode in
+// the order in which they would have been made. Successive modifications of the
+// same path are merged into a single change.
+func (ctx *Context) Changes() []Change {
+	return ctx.changes
+}
+
+// PreviewSet returns the changes that P.Set would make to the object without
+// modifying it.
+func (path P) PreviewSet(obj, value interface{}) ([]Change, error) {
+	ctx := setContext(value, false)
+	ctx.DryRun = true
+
+	err := path.Apply(obj, ctx)
+	return ctx.Changes(), err
+}
+
+// PreviewSetAll returns the changes that P.SetAll would make to the object
+// without modifying it.
+func (path P) PreviewSetAll(obj, value interface{}) ([]Change, error) {
+	ctx := setContext(value, true)
+	ctx.DryRun = true
+
+	err := path.Apply(obj, ctx)
+	return ctx.Changes(), err
+}
+
+// PreviewDelete returns the changes that P.Delete would make to the object
+// without modifying it.
+func (path P) PreviewDelete(obj interface{}) ([]Change, error) {
+	ctx := deleteContext(false)
+	ctx.DryRun = true
+
+	err := path.Apply(obj, ctx)
+	return ctx.Changes(), err
+}
+
+// PreviewDeleteAll returns the changes that P.DeleteAll would make to the
+// object without modifying it.
+func (path P) PreviewDeleteAll(obj interface{}) ([]Change, error) {
+	ctx := deleteContext(true)
+	ctx.DryRun = true
+
+	err := path.Apply(obj, ctx)
+	return ctx.Changes(), err
+}
+
+// record adds a change to the context. A change to the same path as the last
+// recorded change is merged into it such that a value that is created and then
+// written is reported once.
+func (ctx *Context) record(path P, old, value reflect.Value, created bool) {
+	change := Change{
+		Path:    append(P{}, path...),
+		Old:     interfaceOf(old),
+		New:     interfaceOf(value),
+		Created: created,
+	}
+
+	if n := len(ctx.changes); n > 0 && equalPaths(ctx.changes[n-1].Path, path) {
+		ctx.changes[n-1].New = change.New
+		return
+	}
+
+	ctx.changes = append(ctx.changes, change)
+}
+
+func interfaceOf(value reflect.Value) interface{} {
+	if !value.IsValid() || !value.CanInterface() {
+		return nil
+	}
+	return value.Interface()
+}
+
+func equalPaths(a, b P) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
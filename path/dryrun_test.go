@@ -0,0 +1,80 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPreviewSet(t *testing.T) {
+	type Item struct {
+		A int
+	}
+
+	obj := &struct {
+		I  int
+		M  map[string]int
+		MM map[string]map[string]int
+		S  []Item
+		P  *Item
+		C  chan int
+	}{
+		I: 1,
+		M: map[string]int{"x": 1},
+		S: make([]Item, 1, 4),
+		C: make(chan int, 1),
+	}
+
+	preview(t, "I", obj, 2, []Change{{Path: New("I"), Old: 1, New: 2}})
+	preview(t, "M.x", obj, 2, []Change{{Path: New("M.x"), Old: 1, New: 2}})
+	preview(t, "M.y", obj, 3, []Change{{Path: New("M.y"), New: 3, Created: true}})
+	preview(t, "C", obj, 4, []Change{{Path: New("C"), New: 4}})
+
+	preview(t, "P.A", obj, 5, []Change{
+		{Path: New("P"), Old: (*Item)(nil), New: &Item{}, Created: true},
+		{Path: New("P.A"), Old: 0, New: 5},
+	})
+
+	preview(t, "MM.a.b", obj, 6, []Change{
+		{Path: New("MM"), Old: map[string]map[string]int(nil), New: map[string]map[string]int{}, Created: true},
+		{Path: New("MM.a"), New: map[string]int{}, Created: true},
+		{Path: New("MM.a.b"), New: 6, Created: true},
+	})
+
+	preview(t, "S.2.A", obj, 7, []Change{
+		{Path: New("S"), Old: []Item{{}}, New: []Item{{}, {}, {}}, Created: true},
+		{Path: New("S.2.A"), Old: 0, New: 7},
+	})
+
+	if obj.I != 1 || obj.M["x"] != 1 || len(obj.M) != 1 || obj.MM != nil || obj.P != nil || len(obj.C) != 0 {
+		t.Errorf("FAIL: preview modified the object -> %+v", obj)
+	}
+
+	if spare := obj.S[:2]; spare[1] != (Item{}) || len(obj.S) != 1 {
+		t.Errorf("FAIL: preview modified the slice -> %+v", spare)
+	}
+
+	changes, err := New("S.*.A").PreviewSetAll(&struct{ S []Item }{S: []Item{{1}, {2}}}, 8)
+	if err != nil {
+		t.Errorf("FAIL: PreviewSetAll(S.*.A) -> %s", err)
+	}
+
+	exp := []Change{{Path: New("S.0.A"), Old: 1, New: 8}, {Path: New("S.1.A"), Old: 2, New: 8}}
+	if !reflect.DeepEqual(changes, exp) {
+		t.Errorf("FAIL: PreviewSetAll(S.*.A) -> %+v != %+v", changes, exp)
+	}
+
+	if _, err := New("I").PreviewSet(obj, "str"); err == nil {
+		t.Errorf("FAIL: PreviewSet(I) -> expected failure")
+	}
+}
+
+func preview(t *testing.T, path string, obj, value interface{}, exp []Change) {
+	changes, err := New(path).PreviewSet(obj, value)
+	if err != nil {
+		t.Errorf("FAIL(%s): PreviewSet -> %s", path, err)
+	} else if !reflect.DeepEqual(changes, exp) {
+		t.Errorf("FAIL(%s): PreviewSet -> %+v != %+v", path, changes, exp)
+	}
+}
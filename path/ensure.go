@@ -16,46 +16,48 @@ func isNillable(obj reflect.Value) bool {
 	}
 }
 
-func ensure(head, tail P, obj reflect.Value, ctx *Context) error {
+func ensure(head, tail P, obj reflect.Value, ctx *Context) (reflect.Value, error) {
 	if !isNillable(obj) || !obj.IsNil() {
-		return nil
+		return obj, nil
 	}
 
 	// If we're at the end of the path then a nil value is not invalid.
 	if len(tail) == 0 {
-		return nil
+		return obj, nil
 	}
 
 	if !ctx.CreateIfMissing {
-		return ErrMissing
+		return obj, ErrMissing
 	}
 
 	if !obj.CanSet() {
-		return fmt.Errorf("unable to ensure '%s' at '%s'", obj, head)
+		return obj, fmt.Errorf("unable to ensure '%s' at '%s'", obj, head)
 	}
 
+	var value reflect.Value
+
 	switch obj.Kind() {
 
 	case reflect.Ptr:
-		obj.Set(reflect.New(obj.Type().Elem()))
+		value = reflect.New(obj.Type().Elem())
 
 	case reflect.Map:
-		obj.Set(reflect.MakeMap(obj.Type()))
+		value = reflect.MakeMap(obj.Type())
 
 	case reflect.Slice:
-		obj.Set(reflect.MakeSlice(obj.Type(), 0, 0))
+		value = reflect.MakeSlice(obj.Type(), 0, 0)
 
 	case reflect.Chan:
-		obj.Set(reflect.MakeChan(obj.Type(), 1))
+		value = reflect.MakeChan(obj.Type(), 1)
 
 	case reflect.Interface:
-		obj.Set(reflect.Zero(obj.Type()))
+		value = reflect.Zero(obj.Type())
 
 	default:
-		return fmt.Errorf("unable to create '%s' at '%s'", obj, head)
+		return obj, fmt.Errorf("unable to create '%s' at '%s'", obj, head)
 	}
 
-	return nil
+	return ctx.setValue(head, obj, value, true), nil
 }
 
 func zero(head P, mid string, typ reflect.Type) (value reflect.Value, err error) {
@@ -108,7 +110,7 @@ func ensureMapKey(head P, mid string, obj, key reflect.Value, ctx *Context) (val
 		return
 	}
 
-	obj.SetMapIndex(key, value)
+	ctx.setMapIndex(append(head, mid), obj, key, value, true)
 	return
 }
 
@@ -132,6 +134,12 @@ func ensureSliceIndex(
 
 	expanded := obj
 
+	// Appending could write in the spare capacity of the slice which must
+	// remain untouched in dry run mode.
+	if ctx.DryRun && obj.Kind() == reflect.Slice {
+		expanded = reflect.AppendSlice(reflect.MakeSlice(obj.Type(), 0, index+1), obj)
+	}
+
 	for i := obj.Len(); i <= index; i++ {
 		if value, err = zero(head, mid, obj.Type().Elem()); err != nil {
 			return
@@ -144,10 +152,10 @@ func ensureSliceIndex(
 	value = expanded.Index(index)
 
 	if obj.CanSet() {
-		obj.Set(expanded)
+		ctx.setValue(head, obj, expanded, true)
 
 	} else if parent := ctx.Parent(); parent.Kind() == reflect.Map {
-		parent.SetMapIndex(reflect.ValueOf(head.Last()), expanded)
+		ctx.setMapIndex(head, parent, reflect.ValueOf(head.Last()), expanded, true)
 
 	} else {
 		err = fmt.Errorf("value is not addreseable at '%s'", append(head, mid))
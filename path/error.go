@@ -16,3 +16,11 @@ var ErrInvalidType = errors.New("type mismatch")
 
 // ErrNil indicates that value is nil
 var ErrNil = errors.New("value is nil")
+
+// ErrUnresolvable indicates that the type of a path can't be determined without
+// an actual value.
+var ErrUnresolvable = errors.New("type can't be resolved statically")
+
+// ErrOverflow indicates that the result of an arithmetic operation can't be
+// represented by the type of the value being modified.
+var ErrOverflow = errors.New("numeric overflow")
@@ -0,0 +1,224 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// fieldByName returns the field of the struct with the given name which
+// includes the fields promoted from embedded structs. Nil embedded pointers
+// along the way are either created if the context allows it or reported as
+// missing. The returned value is invalid if no such field exists.
+func fieldByName(obj reflect.Value, head P, name string, ctx *Context) (reflect.Value, error) {
+	field, ok := obj.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}, nil
+	}
+
+	value := obj
+
+	for i, index := range field.Index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !ctx.CreateIfMissing {
+					return reflect.Value{}, ErrMissing
+				}
+
+				if !value.CanSet() {
+					return reflect.Value{}, fmt.Errorf("unable to ensure embedded '%s' at '%s'", value.Type(), head)
+				}
+
+				embedded := append(append(P{}, head...), obj.Type().FieldByIndex(field.Index[:i]).Name)
+				value = ctx.setValue(embedded, value, reflect.New(value.Type().Elem()), true)
+			}
+
+			value = value.Elem()
+		}
+
+		value = value.Field(index)
+	}
+
+	return value, nil
+}
+
+// methodByName returns the method with the given name. Methods with a pointer
+// receiver are also considered for structs that are addressable. The returned
+// value is invalid if no such method exists.
+//
+// Reflection doesn't tell whether a method is declared by a struct or promoted
+// from one of its embedded fields. Methods that may be promoted through a nil
+// embedded pointer or interface are therefore wrapped such that the nil pointer
+// dereference caused by calling them is reported as ErrMissing by callGetter.
+// Methods declared by the struct never go through the nil value and are called
+// as usual.
+func methodByName(obj reflect.Value, name string) reflect.Value {
+	if obj.Kind() == reflect.Struct && obj.CanAddr() {
+		obj = obj.Addr()
+	}
+
+	method := obj.MethodByName(name)
+	if method.Kind() == reflect.Invalid || hasPromotedReceiver(obj, name) {
+		return method
+	}
+
+	return guardMethod(method)
+}
+
+// errNilEmbedded is the panic value of the methods wrapped by guardMethod.
+var errNilEmbedded = errors.New("method promoted through a nil embedded value")
+
+// guardMethod wraps the method such that a nil pointer dereference raised by
+// its call panics with errNilEmbedded instead.
+func guardMethod(method reflect.Value) reflect.Value {
+	fn := func(args []reflect.Value) []reflect.Value {
+		defer func() {
+			if r := recover(); r != nil {
+				if err, ok := r.(runtime.Error); ok && strings.Contains(err.Error(), "nil pointer dereference") {
+					panic(errNilEmbedded)
+				}
+				panic(r)
+			}
+		}()
+
+		if method.Type().IsVariadic() {
+			return method.CallSlice(args)
+		}
+		return method.Call(args)
+	}
+
+	return reflect.MakeFunc(method.Type(), fn)
+}
+
+// hasPromotedReceiver walks down the embedded fields through which the method
+// may be promoted and returns false if one of them is nil.
+func hasPromotedReceiver(obj reflect.Value, name string) bool {
+	for obj.Kind() == reflect.Ptr || obj.Kind() == reflect.Interface {
+		if obj.IsNil() {
+			return false
+		}
+		obj = obj.Elem()
+	}
+
+	if obj.Kind() != reflect.Struct {
+		return true
+	}
+
+	i, _ := promotingField(obj.Type(), name, map[reflect.Type]bool{})
+	if i < 0 {
+		return true
+	}
+
+	return hasPromotedReceiver(obj.Field(i), name)
+}
+
+// promotingField returns the index of the embedded field of the struct type
+// that provides the method at the shallowest depth, which is the one selected
+// by the compiler, along with the depth of the method within that field.
+// Returns -1 if no embedded field provides the method or if multiple fields
+// provide it at the same depth.
+func promotingField(typ reflect.Type, name string, visited map[reflect.Type]bool) (index, depth int) {
+	index = -1
+	if visited[typ] {
+		return
+	}
+
+	visited[typ] = true
+	defer delete(visited, typ)
+
+	unique := false
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.Anonymous || !hasMethod(field.Type, name) {
+			continue
+		}
+
+		d := 0
+		if inner := indirectType(field.Type); inner.Kind() == reflect.Struct {
+			if j, n := promotingField(inner, name, visited); j >= 0 {
+				d = n + 1
+			}
+		}
+
+		if index < 0 || d < depth {
+			index, depth, unique = i, d, true
+		} else if d == depth {
+			unique = false
+		}
+	}
+
+	if !unique {
+		index = -1
+	}
+
+	return
+}
+
+func hasMethod(typ reflect.Type, name string) bool {
+	if _, ok := typ.MethodByName(name); ok {
+		return true
+	}
+
+	if typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Interface {
+		_, ok := reflect.PtrTo(typ).MethodByName(name)
+		return ok
+	}
+
+	return false
+}
+
+// matchFieldName returns the name of the field of the struct type that matches
+// the given name according to the match mode. Exact matches always win and the
+// name is returned unchanged if no fields match. Returns an error if multiple
+// fields match the name.
+func matchFieldName(typ reflect.Type, head P, name string, mode MatchMode) (string, error) {
+	if mode == MatchExact {
+		return name, nil
+	}
+
+	if _, ok := typ.FieldByName(name); ok {
+		return name, nil
+	}
+
+	key := normalizeName(name, mode)
+	result := name
+
+	for _, field := range reflect.VisibleFields(typ) {
+		if field.PkgPath != "" || normalizeName(field.Name, mode) != key {
+			continue
+		}
+
+		// Skip the fields that are shadowed or ambiguous since they can't be
+		// referenced by name.
+		if visible, ok := typ.FieldByName(field.Name); !ok || len(visible.Index) != len(field.Index) {
+			continue
+		}
+
+		if result != name && result != field.Name {
+			return "", fmt.Errorf("ambiguous field '%s' matches '%s' and '%s' in type '%s' at '%s'",
+				name, result, field.Name, typ, head)
+		}
+
+		result = field.Name
+	}
+
+	return result, nil
+}
+
+func normalizeName(name string, mode MatchMode) string {
+	if mode == MatchNormalized {
+		name = strings.Map(func(c rune) rune {
+			if c == '_' || c == '-' || c == ' ' {
+				return -1
+			}
+			return c
+		}, name)
+	}
+
+	return strings.ToLower(name)
+}
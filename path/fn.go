@@ -3,7 +3,10 @@
 package path
 
 import (
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 var errorType = reflect.TypeOf((*error)(nil)).Elem()
@@ -27,15 +30,27 @@ func callSetter(fn reflect.Value, value reflect.Value) (err error) {
 }
 
 func isGetter(fn reflect.Value) bool {
-	typ := fn.Type()
+	return fn.Kind() == reflect.Func && !fn.IsNil() && isGetterType(fn.Type())
+}
 
-	return fn.Kind() == reflect.Func && !fn.IsNil() &&
-		(typ.NumIn() == 0) &&
-		(typ.NumOut() == 1 || (typ.NumOut() == 2 && typ.Out(1) == errorType))
+func isGetterType(typ reflect.Type) bool {
+	return typ.NumOut() == 1 || (typ.NumOut() == 2 && typ.Out(1) == errorType)
 }
 
-func callGetter(fn reflect.Value) (result reflect.Value, err error) {
-	results := fn.Call([]reflect.Value{})
+// callGetter calls the getter and returns its result. Methods promoted
+// through a nil embedded value, as wrapped by guardMethod, are reported as
+// ErrMissing.
+func callGetter(fn reflect.Value, args ...reflect.Value) (result reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if r != errNilEmbedded {
+				panic(r)
+			}
+			err = ErrMissing
+		}
+	}()
+
+	results := fn.Call(args)
 
 	result = results[0]
 	if len(results) > 1 && !results[1].IsNil() {
@@ -44,3 +59,150 @@ func callGetter(fn reflect.Value) (result reflect.Value, err error) {
 
 	return
 }
+
+// splitCall splits a function call component of the form 'Name(args)' into its
+// name and its raw argument list. The name is empty for the '()' component.
+func splitCall(item string) (name, args string, ok bool) {
+	if !strings.HasSuffix(item, ")") {
+		return
+	}
+
+	i := strings.IndexByte(item, '(')
+	if i < 0 {
+		return
+	}
+
+	return item[:i], item[i+1 : len(item)-1], true
+}
+
+// parseArgs parses the comma separated list of literals and converts them to
+// the input parameters of the given function type.
+func parseArgs(typ reflect.Type, args string) ([]reflect.Value, error) {
+	items := splitArgs(args)
+
+	if typ.IsVariadic() {
+		if len(items) < typ.NumIn()-1 {
+			return nil, fmt.Errorf("expected at least %d arguments got %d", typ.NumIn()-1, len(items))
+		}
+
+	} else if len(items) != typ.NumIn() {
+		return nil, fmt.Errorf("expected %d arguments got %d", typ.NumIn(), len(items))
+	}
+
+	var values []reflect.Value
+
+	for i, item := range items {
+		var in reflect.Type
+
+		if typ.IsVariadic() && i >= typ.NumIn()-1 {
+			in = typ.In(typ.NumIn() - 1).Elem()
+		} else {
+			in = typ.In(i)
+		}
+
+		value, err := parseArg(item, in)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d -> %s", i, err)
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// splitArgs splits a list of arguments on the commas which are not quoted.
+func splitArgs(args string) (items []string) {
+	if strings.TrimSpace(args) == "" {
+		return
+	}
+
+	var quote rune
+	var escaped bool
+	start := 0
+
+	for i, c := range args {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && c == '\\':
+			escaped = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '`':
+			quote = c
+		case c == ',':
+			items = append(items, strings.TrimSpace(args[start:i]))
+			start = i + 1
+		}
+	}
+
+	return append(items, strings.TrimSpace(args[start:]))
+}
+
+// parseArg converts a string, integer, float or boolean literal into a value of
+// the given type.
+func parseArg(item string, typ reflect.Type) (reflect.Value, error) {
+	var value interface{}
+	var err error
+
+	switch typ.Kind() {
+
+	case reflect.String:
+		value, err = strconv.Unquote(item)
+
+	case reflect.Bool:
+		value, err = strconv.ParseBool(item)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err = strconv.ParseInt(item, 0, typ.Bits())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value, err = strconv.ParseUint(item, 0, typ.Bits())
+
+	case reflect.Float32, reflect.Float64:
+		value, err = strconv.ParseFloat(item, typ.Bits())
+
+	case reflect.Interface:
+		value, err = parseLiteral(item)
+
+	default:
+		err = fmt.Errorf("unsupported parameter type '%s'", typ)
+	}
+
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("invalid literal '%s' for type '%s' -> %s", item, typ, err)
+	}
+
+	result := reflect.ValueOf(value)
+
+	if result.Type().ConvertibleTo(typ) {
+		result = result.Convert(typ)
+	}
+
+	if !result.Type().AssignableTo(typ) {
+		return reflect.Value{}, ErrInvalidType
+	}
+
+	return result, nil
+}
+
+// parseLiteral infers the type of a literal which is then returned as a
+// string, bool, int or float64.
+func parseLiteral(item string) (interface{}, error) {
+	if strings.HasPrefix(item, "\"") || strings.HasPrefix(item, "`") {
+		return strconv.Unquote(item)
+	}
+
+	if item == "true" || item == "false" {
+		return item == "true", nil
+	}
+
+	if value, err := strconv.ParseInt(item, 0, 0); err == nil {
+		return int(value), nil
+	}
+
+	return strconv.ParseFloat(item, 64)
+}
@@ -2,7 +2,9 @@
 
 package path
 
-import ()
+import (
+	"context"
+)
 
 // Get fetches the first value in the given object that matches the
 // path. Returns ErrMissing if the path could not be completed due to a nil
@@ -17,6 +19,18 @@ func (path P) Get(obj interface{}) (result interface{}, err error) {
 	return
 }
 
+// GetContext is similar to Get but aborts with the error of the done context if
+// it is cancelled before the value is found.
+func (path P) GetContext(done context.Context, obj interface{}) (result interface{}, err error) {
+	fn := func(_ P, ctx *Context) (bool, error) {
+		result = ctx.Value().Interface()
+		return false, nil
+	}
+
+	err = path.ApplyContext(done, obj, &Context{Fn: fn})
+	return
+}
+
 // GetAll fetches all the values in the given object that matches the
 // path. Returns ErrMissing if the path could not be completed due to a nil
 // field, a missing array index or a missing map value. Note that missing
@@ -31,3 +45,16 @@ func (path P) GetAll(obj interface{}) (result []interface{}, err error) {
 	err = path.Apply(obj, &Context{Fn: fn})
 	return
 }
+
+// GetAllContext is similar to GetAll but aborts with the error of the done
+// context if it is cancelled before the traversal completes. The values
+// gathered before the cancellation are still returned.
+func (path P) GetAllContext(done context.Context, obj interface{}) (result []interface{}, err error) {
+	fn := func(_ P, ctx *Context) (bool, error) {
+		result = append(result, ctx.Value().Interface())
+		return true, nil
+	}
+
+	err = path.ApplyContext(done, obj, &Context{Fn: fn})
+	return
+}
@@ -3,10 +3,12 @@
 package path
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
 	"testing"
+	"time"
 )
 
 type Interface interface {
@@ -96,6 +98,199 @@ func TestGet(t *testing.T) {
 	getMissing(t, "compound", "W.0.A", compound)
 }
 
+type EmbedStruct struct {
+	GetStruct
+	*CallStruct
+	Interface
+	Y int
+}
+
+type ShadowStruct struct{ *GetStruct }
+
+func (s ShadowStruct) B() int { return -1 }
+
+type ShadowPtrStruct struct{ *GetStruct }
+
+type deepGetter struct{ V int }
+
+func (g *deepGetter) Get() int { return g.V }
+
+type wrap1 struct{ *deepGetter }
+
+type wrap2 struct{ wrap1 }
+
+// DeepFirst declares the field providing Get at depth 3 before the one
+// providing it at depth 2.
+type DeepFirst struct {
+	wrap2
+	wrap1
+}
+
+func (s *ShadowPtrStruct) C() (int, error) { return -2, nil }
+
+func TestGetMethod(t *testing.T) {
+	structVal := GetStruct{100, 1000}
+	getFail(t, "struct", "B.()", structVal)
+	getInt(t, "structAddr", "S.B.()", &struct{ S GetStruct }{structVal}, 100)
+
+	arrayVal := []GetStruct{{1, 0}, {2, 0}}
+	getInt(t, "array", "1.B.()", arrayVal, 2)
+	getInt(t, "array", "0.C()", arrayVal, 1)
+	getAllInt(t, "array", "*.B.()", arrayVal, []int{1, 2})
+
+	mapVal := map[string]GetStruct{"X": {1, 0}}
+	getFail(t, "map", "X.B.()", mapVal)
+
+	embed := &EmbedStruct{GetStruct: GetStruct{10, 20}, Y: 30}
+	getInt(t, "embed", "A", embed, 10)
+	getInt(t, "embed", "GetStruct.Z", embed, 20)
+	getInt(t, "embed", "C.()", embed, 10)
+	getMissing(t, "embed", "L.0", embed)
+	getMissing(t, "embed", "At(0)", embed)
+
+	if err := New("L.0").Set(embed, 40); err != nil {
+		t.Errorf("FAIL(embed): set L.0 -> %s", err)
+	}
+	getInt(t, "embed", "CallStruct.L.0", embed, 40)
+	getInt(t, "embed", "At(0)", embed, 40)
+
+	embed.Interface = &GetStruct{50, 0}
+	getInt(t, "embed", "Interface.B.()", embed, 50)
+
+	// Methods declared by the struct shadow the ones of a nil embedded pointer.
+	getInt(t, "shadow", "B.()", ShadowStruct{}, -1)
+	getInt(t, "shadow", "B.()", &ShadowStruct{}, -1)
+	getInt(t, "shadow", "C()", &ShadowPtrStruct{}, -2)
+	getMissing(t, "shadow", "C()", &ShadowStruct{})
+
+	if value, err := New("B").Get(ShadowStruct{}); err != nil || value == nil {
+		t.Errorf("FAIL(shadow): get B -> %v, %v", value, err)
+	}
+
+	// Methods are called through the shallowest embedded field.
+	getMissing(t, "deep", "Get.()", &DeepFirst{wrap2: wrap2{wrap1{&deepGetter{1}}}})
+	getMissing(t, "deep", "Get()", &DeepFirst{wrap2: wrap2{wrap1{&deepGetter{1}}}})
+	getInt(t, "deep", "Get.()", &DeepFirst{wrap1: wrap1{&deepGetter{2}}}, 2)
+	getInt(t, "deep", "Get()", &DeepFirst{wrap1: wrap1{&deepGetter{2}}}, 2)
+}
+
+func TestGetMatch(t *testing.T) {
+	obj := struct {
+		UserID    int
+		FirstName string
+		Embed     struct{ Zip int }
+		Dup       int
+		DUP       int
+		*GetStruct
+	}{UserID: 1, FirstName: "bob", Dup: 2, DUP: 3, GetStruct: &GetStruct{4, 5}}
+	obj.Embed.Zip = 5
+
+	getMatch(t, MatchExact, "UserID", obj, 1, "UserID")
+	getMatchFail(t, MatchExact, "userid", obj)
+
+	getMatch(t, MatchFold, "userid", obj, 1, "UserID")
+	getMatch(t, MatchFold, "USERID", obj, 1, "UserID")
+	getMatch(t, MatchFold, "embed.zip", obj, 5, "Embed.Zip")
+	getMatch(t, MatchFold, "a", obj, 4, "A")
+	getMatch(t, MatchFold, "Dup", obj, 2, "Dup")
+	getMatchFail(t, MatchFold, "user_id", obj)
+	getMatchFail(t, MatchFold, "dup", obj)
+
+	getMatch(t, MatchNormalized, "user_id", obj, 1, "UserID")
+	getMatch(t, MatchNormalized, "user-id", obj, 1, "UserID")
+	getMatch(t, MatchNormalized, "first_name", obj, "bob", "FirstName")
+	getMatch(t, MatchNormalized, "firstName", obj, "bob", "FirstName")
+	getMatch(t, MatchNormalized, "get_struct.z", obj, 5, "GetStruct.Z")
+	getMatchFail(t, MatchNormalized, "d_u_p", obj)
+}
+
+func getMatch(t *testing.T, mode MatchMode, path string, obj, exp interface{}, expPath string) {
+	var result interface{}
+	var resultPath P
+
+	fn := func(p P, ctx *Context) (bool, error) {
+		result, resultPath = ctx.Value().Interface(), p
+		return false, nil
+	}
+
+	if err := New(path).Apply(obj, &Context{Fn: fn, Match: mode}); err != nil {
+		t.Errorf("FAIL(match): %s -> %s", path, err)
+	} else if result != exp || resultPath.String() != expPath {
+		t.Errorf("FAIL(match): %s -> %s: %v != %s: %v", path, resultPath, result, expPath, exp)
+	}
+}
+
+func getMatchFail(t *testing.T, mode MatchMode, path string, obj interface{}) {
+	fn := func(P, *Context) (bool, error) { return false, nil }
+
+	if err := New(path).Apply(obj, &Context{Fn: fn, Match: mode}); err == nil {
+		t.Errorf("FAIL(match): %s -> expected failure", path)
+	}
+}
+
+type CallStruct struct {
+	M map[string]*GetStruct
+	L []int
+	F func(string, int) int
+}
+
+func (s *CallStruct) Lookup(key string) *GetStruct {
+	return s.M[key]
+}
+
+func (s *CallStruct) At(i uint8) (int, error) {
+	if int(i) >= len(s.L) {
+		return 0, fmt.Errorf("index %d out of range", i)
+	}
+	return s.L[i], nil
+}
+
+func (s *CallStruct) Sum(scale float64, values ...int) int {
+	sum := 0
+	for _, value := range values {
+		sum += value
+	}
+	return int(float64(sum) * scale)
+}
+
+func (s *CallStruct) Pick(ok bool, a, b interface{}) interface{} {
+	if ok {
+		return a
+	}
+	return b
+}
+
+func TestGetCall(t *testing.T) {
+	obj := &CallStruct{
+		M: map[string]*GetStruct{"alice": {10, 0}, "a.b": {20, 0}},
+		L: []int{1, 2, 3},
+		F: func(s string, i int) int { return len(s) + i },
+	}
+
+	getInt(t, "call", `Lookup("alice").A`, obj, 10)
+	getInt(t, "call", `Lookup("a.b").A`, obj, 20)
+	getInt(t, "call", `Lookup("alice").B()`, obj, 10)
+	getInt(t, "call", "Lookup(`alice`).B.()", obj, 10)
+	getInt(t, "call", "At(2)", obj, 3)
+	getInt(t, "call", "At(0x1)", obj, 2)
+	getInt(t, "call", "Sum(1.5)", obj, 0)
+	getInt(t, "call", "Sum(2, 1, 2, 3)", obj, 12)
+	getInt(t, "call", `Pick(true, 1, "a")`, obj, 1)
+	getInt(t, "call", `F.("abc", 1)`, obj, 4)
+
+	getMissing(t, "call", `Lookup("bob").A`, obj)
+	getFail(t, "call", "At(5)", obj)
+	getFail(t, "call", "At(256)", obj)
+	getFail(t, "call", "At(-1)", obj)
+	getFail(t, "call", "At(alice)", obj)
+	getFail(t, "call", "At()", obj)
+	getFail(t, "call", "At(1, 2)", obj)
+	getFail(t, "call", "Lookup(alice)", obj)
+	getFail(t, "call", "Sum()", obj)
+	getFail(t, "call", `F.("abc")`, obj)
+	getFail(t, "call", "Missing(1)", *obj)
+}
+
 func TestGetChan(t *testing.T) {
 	var obj struct{ C chan int }
 	obj.C = make(chan int, 8)
@@ -108,6 +303,86 @@ func TestGetChan(t *testing.T) {
 	getInt(t, "chan", "C.1", &obj, 0)
 	getInt(t, "chan", "C.1", &obj, 1)
 	getAllInt(t, "chan", "C.*", &obj, []int{2, 3, 4, 5, 6, 7})
+	getMissing(t, "chan", "C.1", &obj)
+	getMissing(t, "chan", "C.3", &obj)
+	getFail(t, "chan", "C.-1", &obj)
+	getFail(t, "chan", "C.@abc", &obj)
+}
+
+func TestGetChanCount(t *testing.T) {
+	var obj struct{ C chan int }
+	obj.C = make(chan int, 8)
+
+	for i := 0; i < 8; i++ {
+		obj.C <- i
+	}
+
+	getAllInt(t, "chan", "C.3", &obj, []int{0, 1, 2})
+	getInt(t, "chan", "C.3", &obj, 3)
+	getAllInt(t, "chan", "C.0", &obj, []int{})
+	getAllInt(t, "chan", "C.?", &obj, []int{4, 5, 6, 7})
+	getAllInt(t, "chan", "C.?", &obj, []int{})
+
+	obj.C <- 8
+	obj.C <- 9
+	getAllInt(t, "chan", "C.@10ms", &obj, []int{8, 9})
+
+	obj.C <- 10
+	close(obj.C)
+	getAllInt(t, "chan", "C.5", &obj, []int{10})
+
+	var dest []int
+	obj.C = make(chan int, 2)
+	obj.C <- 11
+	obj.C <- 12
+	readAll(t, "C.?", &obj, &dest)
+	if len(dest) != 2 || dest[0] != 11 || dest[1] != 12 {
+		t.Errorf("FAIL(chan): C.? -> readAll %v", dest)
+	}
+}
+
+func TestGetContext(t *testing.T) {
+	obj := struct {
+		A int
+		C chan int
+	}{A: 1, C: make(chan int, 2)}
+	obj.C <- 1
+	obj.C <- 2
+
+	if value, err := New("A").GetContext(context.Background(), obj); err != nil {
+		t.Errorf("FAIL(context): A -> %s", err)
+	} else if value.(int) != 1 {
+		t.Errorf("FAIL(context): A -> exp 1 got %d", value)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := New("A").GetContext(cancelled, obj); err != context.Canceled {
+		t.Errorf("FAIL(context): A -> expected Canceled got %v", err)
+	}
+
+	timeout, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	result, err := New("C.*").GetAllContext(timeout, obj)
+	if err != context.DeadlineExceeded {
+		t.Errorf("FAIL(context): C.* -> expected DeadlineExceeded got %v", err)
+	}
+	if len(result) != 2 {
+		t.Errorf("FAIL(context): C.* -> invalid length %d != 2", len(result))
+	}
+
+	// The cancellation context doesn't outlive the call.
+	ctx := &Context{Fn: func(P, *Context) (bool, error) { return false, nil }}
+
+	if err := New("A").ApplyContext(cancelled, obj, ctx); err != context.Canceled {
+		t.Errorf("FAIL(context): A -> expected Canceled got %v", err)
+	}
+
+	if err := New("A").Apply(obj, ctx); err != nil {
+		t.Errorf("FAIL(context): reused context -> %s", err)
+	}
 }
 
 func getFail(t *testing.T, title string, path string, obj interface{}) {
@@ -1,54 +1,318 @@
 package path
 
 import (
+	"encoding"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// JSONSchemaDraft is the JSON Schema dialect of the documents generated by
+// JsonSchema.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// JsonSchema returns a JSON Schema document describing the JSON encoding of the
+// given type as produced by encoding/json. Named structs are defined once in
+// the '$defs' section and referenced with '$ref' which also allows recursive
+// types to be described. Fields that are not tagged with omitempty are listed
+// as required and also accept null if they're pointers, slices or maps. Types
+// that can't be encoded, such as channels and functions, are left out.
+//
+// Fields can be annotated with the following struct tags: description, enum
+// (a comma separated list of values), minimum, maximum, pattern, default and
+// example. For slices and arrays, the enum, minimum, maximum and pattern
+// annotations apply to the items. Values are written using the type of the
+// field's schema and JSON literals can be used for default and example values
+// of arrays and objects.
 func JsonSchema(typ reflect.Type) string {
-	mp := map[string]interface{}{}
-	js := jsonSchema(typ, mp)
-	if j, err := json.MarshalIndent(js, "", "    "); err != nil {
+	builder := &schemaBuilder{
+		defs:  make(map[string]interface{}),
+		names: make(map[reflect.Type]string),
+	}
+
+	schema := builder.schema(typ)
+	if schema == nil {
+		schema = map[string]interface{}{}
+	}
+
+	if builder.err != nil {
+		return fmt.Sprint(builder.err)
+	}
+
+	schema["$schema"] = JSONSchemaDraft
+	if len(builder.defs) > 0 {
+		schema["$defs"] = builder.defs
+	}
+
+	j, err := json.MarshalIndent(schema, "", "    ")
+	if err != nil {
 		return fmt.Sprint(err)
-	} else {
-		return strings.Replace(string(j), "\\n", "\n", -1)
 	}
+
+	return string(j)
+}
+
+type schemaBuilder struct {
+	defs  map[string]interface{}
+	names map[reflect.Type]string
+	err   error
 }
 
-func jsonSchema(typ reflect.Type, mp map[string]interface{}) interface{} {
+// schema returns the schema of the given type or nil if the type can't be
+// represented in JSON.
+func (builder *schemaBuilder) schema(typ reflect.Type) map[string]interface{} {
+	typ = indirectType(typ)
+
+	switch typ {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+
+	case durationType:
+		// encoding/json writes durations as an integer number of nanoseconds.
+		return map[string]interface{}{"type": "integer", "description": "Duration in nanoseconds."}
+	}
+
+	if marshals(typ, jsonMarshalerType) {
+		return map[string]interface{}{}
+	}
+
+	if marshals(typ, textMarshalerType) {
+		return map[string]interface{}{"type": "string"}
+	}
 
 	switch typ.Kind() {
 
-	case reflect.Struct:
-		for i := 0; i < typ.NumField(); i++ {
-			field := typ.Field(i)
-
-			var name string
-			list := strings.Split(field.Tag.Get("json"), ",")
-			if len(list) > 0 && list[0] != "" {
-				name = list[0]
-			} else {
-				name = field.Name
-			}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
 
-			mp[name] = jsonSchema(field.Type, map[string]interface{}{})
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+
+	case reflect.Interface:
+		return map[string]interface{}{}
+
+	case reflect.Slice, reflect.Array:
+		if typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}
 		}
 
-	case reflect.Ptr:
-		return jsonSchema(typ.Elem(), map[string]interface{}{})
+		items := builder.schema(typ.Elem())
+		if items == nil {
+			return nil
+		}
 
-	case reflect.Slice:
-		return []interface{}{jsonSchema(typ.Elem(), map[string]interface{}{})}
+		schema := map[string]interface{}{"type": "array", "items": items}
+		if typ.Kind() == reflect.Array {
+			schema["minItems"] = typ.Len()
+			schema["maxItems"] = typ.Len()
+		}
+		return schema
 
 	case reflect.Map:
-		m := map[string]interface{}{}
-		m[typ.Key().String()] = jsonSchema(typ.Elem(), map[string]interface{}{})
-		return m
+		switch typ.Key().Kind() {
+		case reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !typ.Key().Implements(textMarshalerType) {
+				return nil
+			}
+		}
+
+		elem := builder.schema(typ.Elem())
+		if elem == nil {
+			return nil
+		}
+
+		return map[string]interface{}{"type": "object", "additionalProperties": elem}
+
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return builder.structSchema(typ)
+		}
+
+		name, ok := builder.names[typ]
+		if !ok {
+			name = builder.defName(typ)
+			builder.names[typ] = name
+
+			// Reserve the name before crawling the struct which may contain
+			// other types with the same name.
+			builder.defs[name] = nil
+			builder.defs[name] = builder.structSchema(typ)
+		}
+
+		return map[string]interface{}{"$ref": "#/$defs/" + name}
 
 	default:
-		return typ.Kind().String()
+		return nil
 	}
-	return mp
+}
+
+func (builder *schemaBuilder) structSchema(typ reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	for _, field := range tagFields(typ, "json") {
+		schema := builder.schema(field.typ)
+		if schema == nil {
+			continue
+		}
+
+		if hasTagOption(field.opts, "string") {
+			switch indirectType(field.typ).Kind() {
+			case reflect.Bool,
+				reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+				reflect.Float32, reflect.Float64, reflect.String:
+				schema = map[string]interface{}{"type": "string"}
+			}
+		}
+
+		if err := annotateSchema(schema, field.field.Tag); err != nil {
+			builder.err = fmt.Errorf("invalid annotation for field '%s' of type '%s' -> %s", field.path, typ, err)
+		}
+
+		name := field.names.Last()
+
+		// Nil values of fields that are not omitted are written as null.
+		if !hasTagOption(field.opts, "omitempty") && !hasTagOption(field.opts, "omitzero") {
+			required = append(required, name)
+
+			switch field.typ.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map:
+				schema = nullableSchema(schema)
+			}
+		}
+
+		properties[name] = schema
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// nullableSchema extends the schema to also accept null.
+func nullableSchema(schema map[string]interface{}) map[string]interface{} {
+	if typ, ok := schema["type"].(string); ok {
+		schema["type"] = []string{typ, "null"}
+	} else if ref, ok := schema["$ref"]; ok {
+		delete(schema, "$ref")
+		schema["anyOf"] = []interface{}{
+			map[string]interface{}{"$ref": ref},
+			map[string]interface{}{"type": "null"},
+		}
+	}
+
+	return schema
+}
+
+// defName returns a unique name for the type within the '$defs' section.
+func (builder *schemaBuilder) defName(typ reflect.Type) string {
+	name := typ.Name()
+
+	if _, ok := builder.defs[name]; ok {
+		name = strings.Replace(typ.PkgPath(), "/", ".", -1) + "." + name
+	}
+
+	for i := 2; ; i++ {
+		if _, ok := builder.defs[name]; !ok {
+			return name
+		}
+		name = fmt.Sprintf("%s.%d", typ.Name(), i)
+	}
+}
+
+// annotateSchema adds the annotations found in the tags of a struct field to
+// its schema.
+func annotateSchema(schema map[string]interface{}, tag reflect.StructTag) error {
+	if value, ok := tag.Lookup("description"); ok {
+		schema["description"] = value
+	}
+
+	if value, ok := tag.Lookup("default"); ok {
+		schema["default"] = schemaValue(schema, value)
+	}
+
+	if value, ok := tag.Lookup("example"); ok {
+		schema["examples"] = []interface{}{schemaValue(schema, value)}
+	}
+
+	items := schema
+	if schema["type"] == "array" {
+		items = schema["items"].(map[string]interface{})
+	}
+
+	if value, ok := tag.Lookup("enum"); ok {
+		var values []interface{}
+		for _, item := range strings.Split(value, ",") {
+			values = append(values, schemaValue(items, strings.TrimSpace(item)))
+		}
+		items["enum"] = values
+	}
+
+	for _, key := range []string{"minimum", "maximum"} {
+		if value, ok := tag.Lookup(key); ok {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("invalid %s '%s'", key, value)
+			}
+			items[key] = json.Number(value)
+		}
+	}
+
+	if value, ok := tag.Lookup("pattern"); ok {
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("invalid pattern '%s' -> %s", value, err)
+		}
+		items["pattern"] = value
+	}
+
+	return nil
+}
+
+// schemaValue converts the text of an annotation into a value of the type of
+// the schema. Non-string values are parsed as JSON literals and fallback to
+// strings if they're not valid JSON.
+func schemaValue(schema map[string]interface{}, text string) interface{} {
+	if schema["type"] == "string" {
+		return text
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return text
+	}
+
+	return value
+}
+
+// marshals returns true if the type or a pointer to the type implements the
+// marshaler interface given that encoding/json also calls the methods of
+// pointer receivers on addressable values.
+func marshals(typ, marshaler reflect.Type) bool {
+	return typ.Implements(marshaler) || reflect.PtrTo(typ).Implements(marshaler)
 }
@@ -0,0 +1,198 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type SchemaNode struct {
+	Name     string        `json:"name"`
+	Children []*SchemaNode `json:"children,omitempty"`
+	Created  time.Time     `json:"created"`
+	TTL      time.Duration `json:"ttl,omitempty"`
+	Tags     map[string]uint
+	Data     []byte     `json:"data,omitempty"`
+	Fixed    [2]float64 `json:"fixed"`
+	Count    int        `json:"count,string"`
+	Any      interface{}
+	C        chan int
+	Skip     int `json:"-"`
+	*SchemaEmbed
+}
+
+type SchemaEmbed struct {
+	Flag bool `json:"flag"`
+}
+
+func TestJsonSchema(t *testing.T) {
+	exp := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$ref": "#/$defs/SchemaNode",
+		"$defs": {
+			"SchemaNode": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/SchemaNode"}},
+					"created": {"type": "string", "format": "date-time"},
+					"ttl": {"type": "integer", "description": "Duration in nanoseconds."},
+					"Tags": {"type": ["object", "null"], "additionalProperties": {"type": "integer", "minimum": 0}},
+					"data": {"type": "string", "contentEncoding": "base64"},
+					"fixed": {"type": "array", "items": {"type": "number"}, "minItems": 2, "maxItems": 2},
+					"count": {"type": "string"},
+					"Any": {},
+					"flag": {"type": "boolean"}
+				},
+				"required": ["name", "created", "Tags", "fixed", "count", "Any", "flag"]
+			}
+		}
+	}`
+
+	jsonSchemaEqual(t, reflect.TypeOf(SchemaNode{}), exp)
+
+	jsonSchemaEqual(t, reflect.TypeOf([]map[string]*SchemaEmbed{}), `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "array",
+		"items": {"type": "object", "additionalProperties": {"$ref": "#/$defs/SchemaEmbed"}},
+		"$defs": {
+			"SchemaEmbed": {
+				"type": "object",
+				"properties": {"flag": {"type": "boolean"}},
+				"required": ["flag"]
+			}
+		}
+	}`)
+
+	jsonSchemaEqual(t, reflect.TypeOf(struct{ A int }{}), `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {"A": {"type": "integer"}},
+		"required": ["A"]
+	}`)
+
+	// Nil values are written as null unless they're omitted.
+	jsonSchemaEqual(t, reflect.TypeOf(struct {
+		L []int
+		P *int
+		M map[string]int `json:",omitempty"`
+		T *time.Time
+	}{}), `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"L": {"type": ["array", "null"], "items": {"type": "integer"}},
+			"P": {"type": ["integer", "null"]},
+			"M": {"type": "object", "additionalProperties": {"type": "integer"}},
+			"T": {"type": ["string", "null"], "format": "date-time"}
+		},
+		"required": ["L", "P", "T"]
+	}`)
+}
+
+type SchemaConfig struct {
+	Mode    string       `json:"mode" description:"Mode of operation." enum:"fast, slow" default:"fast"`
+	Workers int          `json:"workers" minimum:"1" maximum:"64" default:"4" example:"8"`
+	Ratio   float64      `json:"ratio,omitempty" minimum:"0" maximum:"0.5"`
+	Hosts   []string     `json:"hosts" pattern:"^[a-z.]+$" default:"[\"localhost\"]"`
+	Levels  []int        `json:"levels" enum:"1,2,3"`
+	Node    *SchemaEmbed `json:"node" description:"Embedded node."`
+}
+
+type SchemaText struct{ A, B int }
+
+func (text *SchemaText) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d-%d", text.A, text.B)), nil
+}
+
+type SchemaRaw struct{ A int }
+
+func (raw *SchemaRaw) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprint(raw.A)), nil
+}
+
+func TestJsonSchemaPointerMarshalers(t *testing.T) {
+	jsonSchemaEqual(t, reflect.TypeOf(SchemaText{}), `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "string"
+	}`)
+
+	jsonSchemaEqual(t, reflect.TypeOf(SchemaRaw{}), `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema"
+	}`)
+}
+
+func TestJsonSchemaAnnotations(t *testing.T) {
+	jsonSchemaEqual(t, reflect.TypeOf(SchemaConfig{}), `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$ref": "#/$defs/SchemaConfig",
+		"$defs": {
+			"SchemaConfig": {
+				"type": "object",
+				"properties": {
+					"mode": {
+						"type": "string",
+						"description": "Mode of operation.",
+						"enum": ["fast", "slow"],
+						"default": "fast"
+					},
+					"workers": {
+						"type": "integer",
+						"minimum": 1,
+						"maximum": 64,
+						"default": 4,
+						"examples": [8]
+					},
+					"ratio": {"type": "number", "minimum": 0, "maximum": 0.5},
+					"hosts": {
+						"type": ["array", "null"],
+						"items": {"type": "string", "pattern": "^[a-z.]+$"},
+						"default": ["localhost"]
+					},
+					"levels": {"type": ["array", "null"], "items": {"type": "integer", "enum": [1, 2, 3]}},
+					"node": {
+						"anyOf": [{"$ref": "#/$defs/SchemaEmbed"}, {"type": "null"}],
+						"description": "Embedded node."
+					}
+				},
+				"required": ["mode", "workers", "hosts", "levels", "node"]
+			},
+			"SchemaEmbed": {
+				"type": "object",
+				"properties": {"flag": {"type": "boolean"}},
+				"required": ["flag"]
+			}
+		}
+	}`)
+
+	invalid := reflect.TypeOf(struct {
+		A int `minimum:"abc"`
+	}{})
+
+	var value interface{}
+	if schema := JsonSchema(invalid); json.Unmarshal([]byte(schema), &value) == nil {
+		t.Errorf("FAIL(%s): expected error got\n%s", invalid, schema)
+	}
+}
+
+func jsonSchemaEqual(t *testing.T, typ reflect.Type, exp string) {
+	var expValue, value interface{}
+
+	if err := json.Unmarshal([]byte(exp), &expValue); err != nil {
+		t.Fatal(err)
+	}
+
+	schema := JsonSchema(typ)
+	if err := json.Unmarshal([]byte(schema), &value); err != nil {
+		t.Errorf("FAIL(%s): invalid schema -> %s", typ, err)
+		return
+	}
+
+	if !reflect.DeepEqual(value, expValue) {
+		t.Errorf("FAIL(%s): unexpected schema\n%s", typ, schema)
+	}
+}
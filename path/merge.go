@@ -0,0 +1,386 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MergeOptions controls how Merge combines the values of two objects.
+type MergeOptions struct {
+
+	// Strategies associates path patterns to the strategy used to merge the
+	// values matching the path. Patterns can contain '*' components, which
+	// match any component, and '**' components, which match any number of
+	// components. A strategy is written as a comma separated list containing
+	// one of the following:
+	//
+	//     merge       structs and maps are merged recursively (default).
+	//     replace     the value is replaced by a deep copy of the source.
+	//     append      the elements of the source slice are appended.
+	//     union=Key   the elements of the source slice are merged with the
+	//                 element of the destination slice that has the same value
+	//                 at the Key path or appended if there are none. Elements
+	//                 are compared as a whole if no key is given.
+	//
+	// along with an optional 'skipzero' which prevents zero values of the
+	// source from overwriting the destination (e.g. 'replace,skipzero'). When
+	// multiple patterns match a path, the one with the fewest wildcards is used.
+	Strategies map[string]string
+
+	// SkipZero prevents all the zero values of the source from overwriting the
+	// destination.
+	SkipZero bool
+}
+
+// Merge recursively merges the src object into the dst object which must be a
+// pointer to a value of the same type as src or as the value pointed to by src.
+// By default, the fields of structs and the keys of maps are merged one by one
+// and the values of pointers and interfaces holding the same type are merged
+// recursively while any other value, including slices, is replaced by a deep
+// copy of its source. Structs with unexported fields are replaced as a whole.
+// Nil pointers, slices, maps and interfaces of src are zero values and, like
+// any other zero value, overwrite dst unless zero values are skipped. The
+// strategies of opts can be used to change this behaviour for the values
+// matching a path pattern. A nil src leaves dst unmodified.
+func Merge(dst, src interface{}, opts MergeOptions) error {
+	m, err := newMerger(opts)
+	if err != nil {
+		return err
+	}
+
+	to := reflect.ValueOf(dst)
+	if to.Kind() != reflect.Ptr || to.IsNil() {
+		return fmt.Errorf("merge destination must be a non-nil pointer: %T", dst)
+	}
+
+	if src == nil {
+		return nil
+	}
+
+	from := reflect.ValueOf(src)
+	if from.Type() == to.Type() {
+		if from.IsNil() {
+			return nil
+		}
+		from = from.Elem()
+	}
+
+	if from.Type() != to.Elem().Type() {
+		return ErrInvalidType
+	}
+
+	return m.merge(to.Elem(), from, P{})
+}
+
+type mergeStrategy int
+
+const (
+	mergeRecursive mergeStrategy = iota
+	mergeReplace
+	mergeAppend
+	mergeUnion
+)
+
+type mergeRule struct {
+	pattern   P
+	wildcards int
+	strategy  mergeStrategy
+	key       P
+	skipZero  bool
+}
+
+type merger struct {
+	rules    []mergeRule
+	skipZero bool
+	seen     map[copyKey]bool
+}
+
+func newMerger(opts MergeOptions) (*merger, error) {
+	m := &merger{skipZero: opts.SkipZero, seen: map[copyKey]bool{}}
+
+	var keys []string
+	for key := range opts.Strategies {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		rule, err := parseMergeRule(New(key), opts.Strategies[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid strategy for '%s' -> %s", key, err)
+		}
+
+		m.rules = append(m.rules, rule)
+	}
+
+	return m, nil
+}
+
+func parseMergeRule(pattern P, strategy string) (rule mergeRule, err error) {
+	rule.pattern = pattern
+
+	for _, item := range pattern {
+		if item == "*" || item == "**" {
+			rule.wildcards++
+		}
+	}
+
+	for _, item := range strings.Split(strategy, ",") {
+		name, param := strings.TrimSpace(item), ""
+		if i := strings.Index(name, "="); i >= 0 {
+			name, param = name[:i], name[i+1:]
+		}
+
+		switch name {
+		case "skipzero":
+			rule.skipZero = true
+		case "merge":
+			rule.strategy = mergeRecursive
+		case "replace":
+			rule.strategy = mergeReplace
+		case "append":
+			rule.strategy = mergeAppend
+		case "union":
+			rule.strategy = mergeUnion
+			if param != "" {
+				rule.key = New(param)
+			}
+		default:
+			err = fmt.Errorf("unknown strategy '%s'", name)
+			return
+		}
+	}
+
+	return
+}
+
+// rule returns the rule of the most specific pattern that matches the path.
+func (m *merger) rule(path P) (result mergeRule) {
+	found := false
+
+	for _, rule := range m.rules {
+		if matchPath(rule.pattern, path) && (!found || rule.wildcards < result.wildcards) {
+			result, found = rule, true
+		}
+	}
+
+	return
+}
+
+func (m *merger) skip(rule mergeRule, src reflect.Value) bool {
+	return (m.skipZero || rule.skipZero) && src.IsZero()
+}
+
+func (m *merger) merge(dst, src reflect.Value, path P) error {
+	rule := m.rule(path)
+	if m.skip(rule, src) {
+		return nil
+	}
+
+	switch rule.strategy {
+
+	case mergeReplace:
+		dst.Set(deepCopy(src))
+		return nil
+
+	case mergeAppend:
+		if src.Kind() != reflect.Slice {
+			return fmt.Errorf("unable to append '%s' at '%s'", src.Type(), path)
+		}
+
+		dst.Set(reflect.AppendSlice(dst, deepCopy(src)))
+		return nil
+
+	case mergeUnion:
+		if src.Kind() != reflect.Slice {
+			return fmt.Errorf("unable to union '%s' at '%s'", src.Type(), path)
+		}
+
+		return m.mergeUnion(dst, src, path, rule.key)
+	}
+
+	switch src.Kind() {
+
+	case reflect.Ptr:
+		if src.IsNil() {
+			dst.Set(src)
+			return nil
+		}
+
+		// Cycles in the source, through pointers, maps or slices, are only
+		// merged once.
+		key := copyKey{src.Pointer(), src.Type()}
+		if m.seen[key] {
+			return nil
+		}
+		m.seen[key] = true
+		defer delete(m.seen, key)
+
+		if dst.IsNil() {
+			dst.Set(reflect.New(src.Type().Elem()))
+		}
+
+		return m.merge(dst.Elem(), src.Elem(), path)
+
+	case reflect.Interface:
+		if src.IsNil() || dst.IsNil() || src.Elem().Type() != dst.Elem().Type() {
+			dst.Set(deepCopy(src))
+			return nil
+		}
+
+		value := reflect.New(src.Elem().Type()).Elem()
+		value.Set(dst.Elem())
+
+		if err := m.merge(value, src.Elem(), path); err != nil {
+			return err
+		}
+
+		dst.Set(value)
+		return nil
+
+	case reflect.Struct:
+		if hasUnexportedFields(src.Type()) {
+			dst.Set(deepCopy(src))
+			return nil
+		}
+
+		for i := 0; i < src.NumField(); i++ {
+			if err := m.merge(dst.Field(i), src.Field(i), child(path, src.Type().Field(i).Name)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case reflect.Map:
+		if src.IsNil() {
+			dst.Set(src)
+			return nil
+		}
+
+		key := copyKey{src.Pointer(), src.Type()}
+		if m.seen[key] {
+			return nil
+		}
+		m.seen[key] = true
+		defer delete(m.seen, key)
+
+		if src.Len() > 0 && dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		}
+
+		for _, key := range src.MapKeys() {
+			sub := child(path, fmt.Sprint(key.Interface()))
+			from := src.MapIndex(key)
+
+			to := dst.MapIndex(key)
+			if !to.IsValid() && m.skip(m.rule(sub), from) {
+				continue
+			}
+
+			value := reflect.New(src.Type().Elem()).Elem()
+			if to.IsValid() {
+				value.Set(to)
+			}
+
+			if err := m.merge(value, from, sub); err != nil {
+				return err
+			}
+
+			dst.SetMapIndex(key, value)
+		}
+
+		return nil
+
+	default:
+		dst.Set(deepCopy(src))
+		return nil
+	}
+}
+
+// mergeUnion merges the elements of the src slice into the elements of the dst
+// slice with the same key and appends the others.
+func (m *merger) mergeUnion(dst, src reflect.Value, path P, key P) error {
+	if src.Len() > 0 {
+		seen := copyKey{src.Pointer(), src.Type()}
+		if m.seen[seen] {
+			return nil
+		}
+		m.seen[seen] = true
+		defer delete(m.seen, seen)
+	}
+
+	for i := 0; i < src.Len(); i++ {
+		from := src.Index(i)
+
+		index, err := unionIndex(dst, from, key)
+		if err != nil {
+			return fmt.Errorf("invalid union key '%s' at '%s' -> %s", key, child(path, fmt.Sprint(i)), err)
+		}
+
+		if index < 0 {
+			dst.Set(reflect.Append(dst, deepCopy(from)))
+			continue
+		}
+
+		if key == nil {
+			continue
+		}
+
+		if err := m.merge(dst.Index(index), from, child(path, fmt.Sprint(index))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// unionIndex returns the index of the element of the slice that has the same
+// key as the value or -1 if there are none. Elements are compared as a whole if
+// key is nil.
+func unionIndex(slice, value reflect.Value, key P) (int, error) {
+	var expected interface{} = value.Interface()
+
+	if key != nil {
+		var err error
+		if expected, err = key.Get(value.Interface()); err != nil {
+			return -1, err
+		}
+	}
+
+	for i := 0; i < slice.Len(); i++ {
+		var actual interface{} = slice.Index(i).Interface()
+
+		if key != nil {
+			var err error
+			if actual, err = key.Get(actual); err != nil {
+				return -1, err
+			}
+		}
+
+		if reflect.DeepEqual(actual, expected) {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
+
+func hasUnexportedFields(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).PkgPath != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// child returns a new path made of the path followed by the given component
+// which doesn't share its storage with the path.
+func child(path P, name string) P {
+	return append(path[:len(path):len(path)], name)
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type mergeServer struct {
+	Name  string
+	Port  int
+	Tags  []string
+	Extra map[string]interface{}
+}
+
+type mergeConfig struct {
+	Name     string
+	Debug    bool
+	Timeout  time.Duration
+	Start    time.Time
+	Server   *mergeServer
+	Servers  []mergeServer
+	Hosts    []string
+	Env      map[string]string
+	Limits   map[string]mergeServer
+	Plugins  []string
+	Defaults interface{}
+}
+
+func TestMerge(t *testing.T) {
+	dst := &mergeConfig{
+		Name:    "default",
+		Timeout: time.Second,
+		Server:  &mergeServer{Name: "a", Port: 80, Tags: []string{"x"}},
+		Servers: []mergeServer{{Name: "a", Port: 1}, {Name: "b", Port: 2}},
+		Hosts:   []string{"h1"},
+		Env:     map[string]string{"A": "1", "B": "2"},
+		Limits:  map[string]mergeServer{"a": {Name: "a", Port: 1}},
+		Plugins: []string{"p1", "p2"},
+		Defaults: map[string]interface{}{
+			"x": 1,
+		},
+	}
+
+	start := time.Unix(10, 0)
+
+	src := mergeConfig{
+		Debug:   true,
+		Start:   start,
+		Server:  &mergeServer{Port: 8080, Extra: map[string]interface{}{"k": "v"}},
+		Servers: []mergeServer{{Name: "b", Port: 20}, {Name: "c", Port: 3}},
+		Hosts:   []string{"h2"},
+		Env:     map[string]string{"B": "3", "C": ""},
+		Limits:  map[string]mergeServer{"a": {Port: 10}, "b": {Name: "b"}},
+		Plugins: []string{"p2", "p3"},
+		Defaults: map[string]interface{}{
+			"y": 2,
+		},
+	}
+
+	opts := MergeOptions{
+		Strategies: map[string]string{
+			"Name":         "skipzero",
+			"Server.Name":  "skipzero",
+			"Servers":      "union=Name",
+			"Hosts":        "append",
+			"Env.*":        "skipzero",
+			"Limits.*":     "replace",
+			"Plugins":      "union",
+			"Defaults":     "replace,skipzero",
+			"Server.Extra": "merge",
+		},
+	}
+
+	if err := Merge(dst, src, opts); err != nil {
+		t.Fatalf("FAIL: Merge -> %s", err)
+	}
+
+	exp := &mergeConfig{
+		Name:    "default",
+		Debug:   true,
+		Start:   start,
+		Server:  &mergeServer{Name: "a", Port: 8080, Extra: map[string]interface{}{"k": "v"}},
+		Servers: []mergeServer{{Name: "a", Port: 1}, {Name: "b", Port: 20}, {Name: "c", Port: 3}},
+		Hosts:   []string{"h1", "h2"},
+		Env:     map[string]string{"A": "1", "B": "3"},
+		Limits:  map[string]mergeServer{"a": {Port: 10}, "b": {Name: "b"}},
+		Plugins: []string{"p1", "p2", "p3"},
+		Defaults: map[string]interface{}{
+			"y": 2,
+		},
+	}
+
+	if !reflect.DeepEqual(dst, exp) {
+		t.Errorf("FAIL: Merge -> %+v != %+v", dst, exp)
+	}
+
+	// The merged values are copies of the source.
+	src.Server.Extra["k"] = "w"
+	src.Servers[1].Port = 4
+
+	if dst.Server.Extra["k"] != "v" || dst.Servers[2].Port != 3 {
+		t.Errorf("FAIL: Merge -> shares values with the source")
+	}
+}
+
+func TestMergeSkipZero(t *testing.T) {
+	dst := &mergeServer{Name: "a", Port: 80, Tags: []string{"x"}}
+
+	if err := Merge(dst, &mergeServer{Port: 8080}, MergeOptions{SkipZero: true}); err != nil {
+		t.Errorf("FAIL: Merge -> %s", err)
+	}
+
+	if exp := (&mergeServer{Name: "a", Port: 8080, Tags: []string{"x"}}); !reflect.DeepEqual(dst, exp) {
+		t.Errorf("FAIL: Merge -> %+v != %+v", dst, exp)
+	}
+
+	if err := Merge(dst, mergeServer{Port: 1}, MergeOptions{}); err != nil {
+		t.Errorf("FAIL: Merge -> %s", err)
+	}
+
+	if exp := (&mergeServer{Port: 1}); !reflect.DeepEqual(dst, exp) {
+		t.Errorf("FAIL: Merge -> %+v != %+v", dst, exp)
+	}
+
+	if err := Merge(dst, nil, MergeOptions{}); err != nil || dst.Port != 1 {
+		t.Errorf("FAIL: Merge(nil) -> %+v, %v", dst, err)
+	}
+}
+
+func TestMergeNil(t *testing.T) {
+	type C struct {
+		P *int
+		S []int
+		M map[string]int
+		I interface{}
+	}
+
+	full := func() *C {
+		return &C{P: intPtr(1), S: []int{1}, M: map[string]int{"a": 1}, I: 1}
+	}
+
+	dst := full()
+	if err := Merge(dst, C{}, MergeOptions{SkipZero: true}); err != nil || !reflect.DeepEqual(dst, full()) {
+		t.Errorf("FAIL: Merge(skipzero) -> %+v, %v", dst, err)
+	}
+
+	if err := Merge(dst, C{}, MergeOptions{}); err != nil || !reflect.DeepEqual(dst, &C{}) {
+		t.Errorf("FAIL: Merge -> %+v, %v", dst, err)
+	}
+
+	// Empty but non-nil maps are merged.
+	dst = full()
+	if err := Merge(dst, C{M: map[string]int{}}, MergeOptions{}); err != nil || dst.M["a"] != 1 {
+		t.Errorf("FAIL: Merge(empty) -> %+v, %v", dst, err)
+	}
+}
+
+func TestMergeCycle(t *testing.T) {
+	src := map[string]interface{}{"a": 1}
+	src["self"] = src
+
+	dst := map[string]interface{}{"b": 2}
+	dst["self"] = dst
+
+	if err := Merge(&dst, src, MergeOptions{}); err != nil {
+		t.Fatalf("FAIL: Merge -> %s", err)
+	}
+
+	if dst["a"] != 1 || dst["b"] != 2 {
+		t.Errorf("FAIL: Merge -> %v", dst)
+	}
+
+	if reflect.ValueOf(dst["self"]).Pointer() != reflect.ValueOf(dst).Pointer() {
+		t.Errorf("FAIL: Merge -> cycle not preserved")
+	}
+}
+
+func TestMergeFail(t *testing.T) {
+	dst := &mergeConfig{}
+
+	mergeFail(t, "nil", nil, mergeConfig{}, MergeOptions{})
+	mergeFail(t, "value", mergeConfig{}, mergeConfig{}, MergeOptions{})
+	mergeFail(t, "type", dst, mergeServer{}, MergeOptions{})
+	mergeFail(t, "strategy", dst, mergeConfig{}, MergeOptions{Strategies: map[string]string{"Name": "bob"}})
+	mergeFail(t, "append", dst, mergeConfig{Name: "a"}, MergeOptions{Strategies: map[string]string{"Name": "append"}})
+	mergeFail(t, "union", dst, mergeConfig{Hosts: []string{"a"}}, MergeOptions{Strategies: map[string]string{"Hosts": "union=Name"}})
+}
+
+func mergeFail(t *testing.T, title string, dst, src interface{}, opts MergeOptions) {
+	if err := Merge(dst, src, opts); err == nil {
+		t.Errorf("FAIL(%s): Merge -> expected failure", title)
+	}
+}
+
+func TestMergeRecursivePattern(t *testing.T) {
+	dst := &mergeConfig{
+		Server:  &mergeServer{Tags: []string{"a"}},
+		Servers: []mergeServer{{Tags: []string{"b"}}},
+		Hosts:   []string{"h1"},
+	}
+
+	src := mergeConfig{
+		Server:  &mergeServer{Tags: []string{"c"}},
+		Servers: []mergeServer{{Tags: []string{"d"}}},
+		Hosts:   []string{"h2"},
+	}
+
+	opts := MergeOptions{
+		Strategies: map[string]string{
+			"**.Tags": "append",
+			"Servers": "union",
+		},
+	}
+
+	if err := Merge(dst, src, opts); err != nil {
+		t.Fatalf("FAIL: Merge -> %s", err)
+	}
+
+	if exp := []string{"a", "c"}; !reflect.DeepEqual(dst.Server.Tags, exp) {
+		t.Errorf("FAIL: Merge(Server.Tags) -> %v != %v", dst.Server.Tags, exp)
+	}
+
+	if exp := []mergeServer{{Tags: []string{"b"}}, {Tags: []string{"d"}}}; !reflect.DeepEqual(dst.Servers, exp) {
+		t.Errorf("FAIL: Merge(Servers) -> %v != %v", dst.Servers, exp)
+	}
+
+	if exp := []string{"h2"}; !reflect.DeepEqual(dst.Hosts, exp) {
+		t.Errorf("FAIL: Merge(Hosts) -> %v != %v", dst.Hosts, exp)
+	}
+
+	// The pattern with the fewest wildcards wins.
+	opts.Strategies = map[string]string{"**.Tags": "append", "Server.Tags": "replace"}
+	dst = &mergeConfig{Server: &mergeServer{Tags: []string{"a"}}}
+
+	if err := Merge(dst, mergeConfig{Server: &mergeServer{Tags: []string{"c"}}}, opts); err != nil {
+		t.Fatalf("FAIL: Merge -> %s", err)
+	}
+
+	if exp := []string{"c"}; !reflect.DeepEqual(dst.Server.Tags, exp) {
+		t.Errorf("FAIL: Merge(Server.Tags) -> %v != %v", dst.Server.Tags, exp)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	checkMatchPath(t, "A.B", "A.B", true)
+	checkMatchPath(t, "A.*", "A.B", true)
+	checkMatchPath(t, "A.*", "A.B.C", false)
+	checkMatchPath(t, "**", "A.B.C", true)
+	checkMatchPath(t, "**.C", "C", true)
+	checkMatchPath(t, "**.C", "A.B.C", true)
+	checkMatchPath(t, "**.C", "A.C.B", false)
+	checkMatchPath(t, "A.**.C", "A.C", true)
+	checkMatchPath(t, "A.**.C.*", "A.B.C.D", true)
+	checkMatchPath(t, "A.**.C.*", "A.B.C", false)
+	checkMatchPath(t, "A.B.**", "A", false)
+}
+
+func checkMatchPath(t *testing.T, pattern, path string, exp bool) {
+	if result := matchPath(New(pattern), New(path)); result != exp {
+		t.Errorf("FAIL(%s): matchPath(%s) -> %t != %t", pattern, path, result, exp)
+	}
+}
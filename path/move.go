@@ -0,0 +1,131 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Move writes the value located at the from path to the to path and removes it
+// from the from path as in P.Delete. Missing components of the to path are
+// created. Moving a value onto its own path does nothing while moving it into
+// or out of itself, where one path is a prefix of the other, returns an error.
+// If any step fails then the object is left unmodified.
+func Move(obj interface{}, from, to P) error {
+	if overlaps(from, to) {
+		return fmt.Errorf("unable to move '%s' to '%s' which overlap", from, to)
+	}
+
+	value, err := readValue(from, obj)
+	if err != nil || equalPaths(from, to) {
+		return err
+	}
+
+	tx := new(Tx)
+
+	if err := tx.Apply(to, obj, writeContext(value)); err != nil {
+		return err
+	}
+
+	if err := tx.Apply(from, obj, deleteContext(false)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// Copy writes a deep copy of the value located at the from path to the to path
+// such that modifying one doesn't modify the other. Missing components of the
+// to path are created.
+func Copy(obj interface{}, from, to P) error {
+	value, err := readValue(from, obj)
+	if err != nil {
+		return err
+	}
+
+	return to.Apply(obj, writeContext(deepCopy(value)))
+}
+
+// Swap exchanges the values located at the a and b paths which must both
+// exist. Swapping a value with a value it contains, where one path is a prefix
+// of the other, returns an error. If any step fails then the object is left
+// unmodified.
+func Swap(obj interface{}, a, b P) error {
+	if overlaps(a, b) {
+		return fmt.Errorf("unable to swap '%s' and '%s' which overlap", a, b)
+	}
+
+	valueA, err := readValue(a, obj)
+	if err != nil {
+		return err
+	}
+
+	valueB, err := readValue(b, obj)
+	if err != nil {
+		return err
+	}
+
+	tx := new(Tx)
+
+	if err := tx.Apply(a, obj, writeContext(valueB)); err != nil {
+		return err
+	}
+
+	if err := tx.Apply(b, obj, writeContext(valueA)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// overlaps returns true if one of the paths is a strict prefix of the other.
+func overlaps(a, b P) bool {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+
+	return len(a) != len(b) && equalPaths(a[:n], b[:n])
+}
+
+// readValue returns a copy of the first value that matches the path.
+// Interfaces are unwrapped and an invalid value is returned for a nil
+// interface.
+func readValue(path P, obj interface{}) (value reflect.Value, err error) {
+	fn := func(_ P, ctx *Context) (bool, error) {
+		value = ctx.Value()
+		return false, nil
+	}
+
+	if err = path.Apply(obj, &Context{Fn: fn}); err != nil {
+		return
+	}
+
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	if value.IsValid() {
+		result := reflect.New(value.Type()).Elem()
+		result.Set(value)
+		value = result
+	}
+
+	return
+}
+
+// writeContext returns a context which sets the given value on the first match.
+// An invalid value sets the zero value.
+func writeContext(value reflect.Value) *Context {
+	fn := func(p P, ctx *Context) (bool, error) {
+		if !value.IsValid() {
+			return false, set(p, ctx, reflect.Zero(ctx.Value().Type()))
+		}
+		return false, set(p, ctx, value)
+	}
+
+	return &Context{CreateIfMissing: true, Fn: fn}
+}
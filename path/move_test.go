@@ -0,0 +1,198 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+	"testing"
+)
+
+type moveItem struct {
+	A    int
+	Tags []string
+	Next *moveItem
+}
+
+type moveStruct struct {
+	I  int
+	J  int
+	P  *moveItem
+	Q  *moveItem
+	S  []int
+	M  map[string]int
+	MI map[string]*moveItem
+	V  interface{}
+	W  map[string]interface{}
+}
+
+func TestMove(t *testing.T) {
+	obj := &moveStruct{
+		I: 1,
+		P: &moveItem{A: 1},
+		M: map[string]int{"a": 1},
+		V: 2,
+	}
+
+	if err := Move(obj, New("I"), New("M.b")); err != nil {
+		t.Errorf("FAIL: Move(I, M.b) -> %s", err)
+	}
+
+	getInt(t, "Move", "M.b", obj, 1)
+	getInt(t, "Move", "I", obj, 0)
+
+	if err := Move(obj, New("M.a"), New("MI.x.A")); err != nil {
+		t.Errorf("FAIL: Move(M.a, MI.x.A) -> %s", err)
+	}
+
+	getInt(t, "Move", "MI.x.A", obj, 1)
+	getMissing(t, "Move", "M.a", obj)
+
+	if err := Move(obj, New("V"), New("J")); err != nil {
+		t.Errorf("FAIL: Move(V, J) -> %s", err)
+	}
+
+	getInt(t, "Move", "J", obj, 2)
+
+	if obj.V != nil {
+		t.Errorf("FAIL: Move(V, J) -> %v != nil", obj.V)
+	}
+
+	if err := Move(obj, New("P"), New("Q")); err != nil || obj.P != nil || obj.Q.A != 1 {
+		t.Errorf("FAIL: Move(P, Q) -> %v, %v, %v", obj.P, obj.Q, err)
+	}
+
+	if err := Move(obj, New("M.x"), New("I")); err != ErrMissing {
+		t.Errorf("FAIL: Move(M.x, I) -> %v != %v", err, ErrMissing)
+	}
+
+	if err := Move(obj, New("Q"), New("I")); err != ErrInvalidType || obj.Q == nil {
+		t.Errorf("FAIL: Move(Q, I) -> %v != %v", err, ErrInvalidType)
+	}
+
+	// Moving a value onto itself leaves it in place.
+	if err := Move(obj, New("MI.x.A"), New("MI.x.A")); err != nil {
+		t.Errorf("FAIL: Move(MI.x.A, MI.x.A) -> %s", err)
+	}
+	getInt(t, "Move", "MI.x.A", obj, 1)
+
+	if err := Move(obj, New("M.x"), New("M.x")); err != ErrMissing {
+		t.Errorf("FAIL: Move(M.x, M.x) -> %v != %v", err, ErrMissing)
+	}
+
+	// Moving a value into or out of itself is rejected.
+	if err := Move(obj, New("Q"), New("Q.Next")); err == nil || obj.Q == nil || obj.Q.Next != nil {
+		t.Errorf("FAIL: Move(Q, Q.Next) -> expected error")
+	}
+
+	if err := Move(obj, New("MI.x.A"), New("MI")); err == nil {
+		t.Errorf("FAIL: Move(MI.x.A, MI) -> expected error")
+	}
+	getInt(t, "Move", "MI.x.A", obj, 1)
+}
+
+func TestCopy(t *testing.T) {
+	item := &moveItem{A: 1, Tags: []string{"a"}}
+	item.Next = item
+
+	obj := &moveStruct{P: item, W: map[string]interface{}{"x": []int{1}}}
+
+	if err := Copy(obj, New("P"), New("MI.a")); err != nil {
+		t.Errorf("FAIL: Copy(P, MI.a) -> %s", err)
+	}
+
+	result := obj.MI["a"]
+
+	if result == item || result.Next != result || !reflect.DeepEqual(result.Tags, item.Tags) {
+		t.Errorf("FAIL: Copy(P, MI.a) -> %+v", result)
+	}
+
+	result.Tags[0] = "b"
+	if item.Tags[0] != "a" {
+		t.Errorf("FAIL: Copy(P, MI.a) -> shared slice")
+	}
+
+	if err := Copy(obj, New("W.x"), New("W.y")); err != nil {
+		t.Errorf("FAIL: Copy(W.x, W.y) -> %s", err)
+	}
+
+	obj.W["y"].([]int)[0] = 2
+	getInt(t, "Copy", "W.x.0", obj, 1)
+
+	// Maps and slices that contain themselves are copied once.
+	list := []interface{}{nil}
+	list[0] = list
+	obj.W["self"], obj.W["list"] = obj.W, list
+
+	if err := Copy(obj, New("W"), New("V")); err != nil {
+		t.Errorf("FAIL: Copy(W, V) -> %s", err)
+	}
+
+	copied := obj.V.(map[string]interface{})
+	if reflect.ValueOf(copied).Pointer() == reflect.ValueOf(obj.W).Pointer() ||
+		reflect.ValueOf(copied["self"]).Pointer() != reflect.ValueOf(copied).Pointer() {
+		t.Errorf("FAIL: Copy(W, V) -> map cycle not preserved")
+	}
+
+	copiedList := copied["list"].([]interface{})
+	if &copiedList[0] == &list[0] || &copiedList[0].([]interface{})[0] != &copiedList[0] {
+		t.Errorf("FAIL: Copy(W, V) -> slice cycle not preserved")
+	}
+}
+
+func TestSwap(t *testing.T) {
+	obj := &moveStruct{
+		I: 1,
+		J: 2,
+		P: &moveItem{A: 1},
+		M: map[string]int{"a": 3, "b": 4},
+		V: 5,
+	}
+
+	swap(t, obj, "I", "J")
+	getInt(t, "Swap", "I", obj, 2)
+	getInt(t, "Swap", "J", obj, 1)
+
+	swap(t, obj, "M.a", "M.b")
+	getInt(t, "Swap", "M.a", obj, 4)
+	getInt(t, "Swap", "M.b", obj, 3)
+
+	swap(t, obj, "I", "M.a")
+	getInt(t, "Swap", "I", obj, 4)
+	getInt(t, "Swap", "M.a", obj, 2)
+
+	swap(t, obj, "P", "Q")
+
+	if obj.P != nil || obj.Q.A != 1 {
+		t.Errorf("FAIL: Swap(P, Q) -> %v, %v", obj.P, obj.Q)
+	}
+
+	swap(t, obj, "J", "V")
+	getInt(t, "Swap", "J", obj, 5)
+	getInt(t, "Swap", "V", obj, 1)
+
+	if err := Swap(obj, New("I"), New("M.c")); err != ErrMissing {
+		t.Errorf("FAIL: Swap(I, M.c) -> %v != %v", err, ErrMissing)
+	}
+
+	// Swapping a value with a value it contains is rejected.
+	m := map[string]interface{}{"x": map[string]interface{}{"y": map[string]interface{}{"z": 1}}}
+	if err := Swap(m, New("x"), New("x.y")); err == nil {
+		t.Errorf("FAIL: Swap(x, x.y) -> expected error")
+	}
+
+	if err := Swap(m, New("x.y"), New("x")); err == nil {
+		t.Errorf("FAIL: Swap(x.y, x) -> expected error")
+	}
+	getInt(t, "Swap", "x.y.z", m, 1)
+
+	// Setting the second value fails so the first must be rolled back.
+	if err := Swap(obj, New("Q"), New("V")); err == nil || obj.Q == nil || obj.V != 1 {
+		t.Errorf("FAIL: Swap(Q, V) -> %v, %v, %v", obj.Q, obj.V, err)
+	}
+}
+
+func swap(t *testing.T, obj interface{}, a, b string) {
+	if err := Swap(obj, New(a), New(b)); err != nil {
+		t.Errorf("FAIL: Swap(%s, %s) -> %s", a, b, err)
+	}
+}
@@ -13,12 +13,21 @@ import (
 // slice indexes should be specified using non-negative numbers. Only map keyed
 // with string are currently supported. Channels can be read by providing either
 // a number of values to read or a wildcard character to read all values until
-// the channel is closed. To call through a function, specify the '()'.
+// the channel is closed. A '?' component reads the values currently buffered in
+// a channel without blocking and a '@' component followed by a duration reads
+// all values until the channel is closed or the duration expires. To call
+// through a function, specify the '()'. Methods can also be called with literal
+// arguments using a component of the form 'Name(arg1, arg2)'.
 type P []string
 
-// New returns a new P object from a given path string.
+// New returns a new P object from a given path string. Separators that appear
+// within the argument list of a function call component are not split on.
 func New(path string) P {
-	return strings.Split(path, ".")
+	if strings.IndexByte(path, '(') < 0 {
+		return strings.Split(path, ".")
+	}
+
+	return split(path)
 }
 
 // Newf returns a new P object from the given format strings applied to
@@ -44,3 +53,58 @@ func (path P) String() string {
 
 // Last returns the last component of the path.
 func (path P) Last() string { return path[len(path)-1] }
+
+// split breaks the path on '.' characters that are not nested within
+// parenthesis or quoted within a function call argument list.
+func split(path string) (result P) {
+	var depth int
+	var quote rune
+	var escaped bool
+	start := 0
+
+	for i, c := range path {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && c == '\\':
+			escaped = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case depth > 0 && (c == '"' || c == '`'):
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == '.' && depth == 0:
+			result = append(result, path[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(result, path[start:])
+}
+
+// matchPath returns true if the path matches the pattern where '*' components
+// of the pattern match any component of the path and '**' components match any
+// number of components, including none.
+func matchPath(pattern, path P) bool {
+	for i, item := range pattern {
+		if item == "**" {
+			for j := i; j <= len(path); j++ {
+				if matchPath(pattern[i+1:], path[j:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if i >= len(path) || item != "*" && item != path[i] {
+			return false
+		}
+	}
+
+	return len(pattern) == len(path)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+)
+
+// PathOptions controls the paths enumerated by Paths.
+type PathOptions struct {
+
+	// Getters includes the methods and function fields that can be called
+	// without arguments. Their components are written using the call syntax
+	// (e.g. 'Name()' or 'Fn.()').
+	Getters bool
+
+	// MaxDepth limits the number of components of the enumerated paths. Zero
+	// means no limit.
+	MaxDepth int
+}
+
+// PathType associates a path pattern to the type of the values it reaches.
+type PathType struct {
+	Path P
+	Type reflect.Type
+}
+
+// Paths enumerates the path patterns that can be applied to a value of the
+// given type along with the types they reach. Intermediate paths are included
+// such that 'Users', 'Users.*' and 'Users.*.Name' are all returned. Slices,
+// arrays and maps are expanded using a wildcard component. Channels are left
+// out since reading from them consumes their values. Interfaces and PathGetter
+// implementations can only be explored with an actual value and are therefore
+// returned as leaves. Recursive types are expanded once.
+func Paths(typ reflect.Type, opts PathOptions) []PathType {
+	var result []PathType
+	paths(typ, P{}, opts, map[reflect.Type]bool{}, &result)
+	return result
+}
+
+func paths(typ reflect.Type, head P, opts PathOptions, visited map[reflect.Type]bool, result *[]PathType) {
+	if len(head) > 0 {
+		*result = append(*result, PathType{head, typ})
+	}
+
+	if opts.MaxDepth > 0 && len(head) >= opts.MaxDepth {
+		return
+	}
+
+	if typ.Implements(pathGetterType) || reflect.PtrTo(typ).Implements(pathGetterType) {
+		return
+	}
+
+	if visited[typ] {
+		return
+	}
+
+	visited[typ] = true
+	defer delete(visited, typ)
+
+	next := func(mid string, typ reflect.Type) {
+		paths(typ, append(head[:len(head):len(head)], mid), opts, visited, result)
+	}
+
+	if opts.Getters {
+		pathsToGetters(typ, next)
+	}
+
+	pathsToElems(typ, opts, next)
+}
+
+// pathsToElems enumerates the components that lead to the fields or elements of
+// the type. Pointers are dereferenced such that the elements of pointers to
+// slices, arrays and maps are also reached.
+func pathsToElems(typ reflect.Type, opts PathOptions, next func(string, reflect.Type)) {
+	switch typ.Kind() {
+
+	case reflect.Ptr:
+		pathsToElems(typ.Elem(), opts, next)
+
+	case reflect.Struct:
+		pathsToFields(typ, next)
+
+	case reflect.Array, reflect.Slice:
+		next("*", typ.Elem())
+
+	case reflect.Map:
+		if typ.Key().Kind() == reflect.String {
+			next("*", typ.Elem())
+		}
+
+	case reflect.Func:
+		if opts.Getters && typ.NumIn() == 0 && isGetterType(typ) {
+			next("()", typ.Out(0))
+		}
+	}
+}
+
+func pathsToFields(typ reflect.Type, next func(string, reflect.Type)) {
+	for i := 0; i < typ.NumField(); i++ {
+		if field := typ.Field(i); field.PkgPath == "" {
+			next(field.Name, field.Type)
+		}
+	}
+}
+
+func pathsToGetters(typ reflect.Type, next func(string, reflect.Type)) {
+	if typ.Kind() == reflect.Struct {
+		typ = reflect.PtrTo(typ)
+	}
+
+	for i := 0; i < typ.NumMethod(); i++ {
+		name := typ.Method(i).Name
+
+		if method, ok := staticMethod(typ, name); ok && method.NumIn() == 0 && isGetterType(method) {
+			next(name+"()", method.Out(0))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type PathsNode struct {
+	Name     string
+	Children map[string]*PathsNode
+	hidden   int
+}
+
+func (n *PathsNode) Count() int { return len(n.Children) }
+
+func (n *PathsNode) Child(name string) *PathsNode { return n.Children[name] }
+
+func TestPaths(t *testing.T) {
+	typ := reflect.TypeOf(struct {
+		Users []struct {
+			Address *struct{ City string }
+			Tags    []string
+		}
+		Root  *PathsNode
+		Any   interface{}
+		C     chan int
+		F     func() (int, error)
+		M     map[int]string
+		Proxy *Record
+	}{})
+
+	checkPaths(t, typ, PathOptions{}, `
+		Users []struct { Address *struct { City string }; Tags []string }
+		Users.* struct { Address *struct { City string }; Tags []string }
+		Users.*.Address *struct { City string }
+		Users.*.Address.City string
+		Users.*.Tags []string
+		Users.*.Tags.* string
+		Root *path.PathsNode
+		Root.Name string
+		Root.Children map[string]*path.PathsNode
+		Root.Children.* *path.PathsNode
+		Any interface {}
+		C chan int
+		F func() (int, error)
+		M map[int]string
+		Proxy *path.Record`)
+
+	checkPaths(t, reflect.TypeOf(PathsNode{}), PathOptions{Getters: true, MaxDepth: 2}, `
+		Count() int
+		Name string
+		Children map[string]*path.PathsNode
+		Children.* *path.PathsNode`)
+
+	checkPaths(t, reflect.TypeOf(struct{ F func() int }{}), PathOptions{Getters: true}, `
+		F func() int
+		F.() int`)
+
+	// Pointers to slices, arrays and maps are expanded like their elements.
+	checkPaths(t, reflect.TypeOf(struct {
+		L *[]int
+		A *[2]string
+		M **map[string]bool
+	}{}), PathOptions{}, `
+		L *[]int
+		L.* int
+		A *[2]string
+		A.* string
+		M **map[string]bool
+		M.* bool`)
+}
+
+func checkPaths(t *testing.T, typ reflect.Type, opts PathOptions, exp string) {
+	var result []string
+	for _, item := range Paths(typ, opts) {
+		result = append(result, fmt.Sprintf("%s %s", item.Path, item.Type))
+	}
+
+	var expected []string
+	for _, line := range strings.Split(strings.TrimSpace(exp), "\n") {
+		expected = append(expected, strings.TrimSpace(line))
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("FAIL(%s): unexpected paths\n%s", typ, strings.Join(result, "\n"))
+	}
+}
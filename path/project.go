@@ -0,0 +1,237 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Project returns a deep copy of the object which only contains the values
+// selected by the given paths while everything else is left to its zero value.
+// The returned value has the same type as the object. Paths can contain
+// wildcards and may only go through fields, slice and array indexes and map
+// keys. Slices of the projection keep the length of the original so that their
+// indexes are preserved. Missing values are ignored but an error is returned
+// for paths that can't exist in the object, such as an unknown field.
+func Project(obj interface{}, paths ...P) (interface{}, error) {
+	if obj == nil {
+		return nil, ErrNil
+	}
+
+	src := reflect.ValueOf(obj)
+	dst := reflect.New(src.Type()).Elem()
+
+	for _, path := range paths {
+		if err := project(dst, src, P{}, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return dst.Interface(), nil
+}
+
+// ProjectMap is similar to Project but returns the selected values in nested
+// maps keyed by the components of their concrete path. As an example,
+// selecting 'A.B' returns a map containing the key 'A' associated with a map
+// containing the key 'B' associated with a copy of the value. Since the values
+// are read like GetAll, paths can also go through functions and methods.
+func ProjectMap(obj interface{}, paths ...P) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+
+	fn := func(p P, ctx *Context) (bool, error) {
+		insertPath(result, p, copyOf(ctx.Value()))
+		return true, nil
+	}
+
+	for _, path := range paths {
+		if err := path.Apply(obj, &Context{Fn: fn}); err != nil && err != ErrMissing {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func project(dst, src reflect.Value, head, tail P) error {
+	if len(tail) == 0 {
+		dst.Set(deepCopy(src))
+		return nil
+	}
+
+	switch src.Kind() {
+
+	case reflect.Ptr:
+		if src.IsNil() {
+			return nil
+		}
+
+		if dst.IsNil() {
+			dst.Set(reflect.New(src.Type().Elem()))
+		}
+
+		return project(dst.Elem(), src.Elem(), head, tail)
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return nil
+		}
+
+		value := reflect.New(src.Elem().Type()).Elem()
+		if !dst.IsNil() && dst.Elem().Type() == value.Type() {
+			value.Set(dst.Elem())
+		}
+
+		if err := project(value, src.Elem(), head, tail); err != nil {
+			return err
+		}
+
+		dst.Set(value)
+		return nil
+
+	case reflect.Struct:
+		return projectStruct(dst, src, head, tail[0], tail[1:])
+
+	case reflect.Array, reflect.Slice:
+		return projectSlice(dst, src, head, tail[0], tail[1:])
+
+	case reflect.Map:
+		return projectMap(dst, src, head, tail[0], tail[1:])
+
+	default:
+		return fmt.Errorf("unable to project '%s' in type '%s' at '%s'", tail[0], src.Type(), head)
+	}
+}
+
+func projectStruct(dst, src reflect.Value, head P, mid string, tail P) error {
+	if mid == "*" {
+		typ := src.Type()
+
+		for i := 0; i < typ.NumField(); i++ {
+			if typ.Field(i).PkgPath != "" {
+				continue
+			}
+
+			if err := project(dst.Field(i), src.Field(i), child(head, typ.Field(i).Name), tail); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if field, ok := src.Type().FieldByName(mid); !ok || field.PkgPath != "" {
+		return fmt.Errorf("no field '%s' in type '%s' at '%s'", mid, src.Type(), head)
+	}
+
+	from, err := fieldByName(src, head, mid, &Context{})
+	if err == ErrMissing {
+		return nil
+	}
+
+	to, err := fieldByName(dst, head, mid, &Context{CreateIfMissing: true})
+	if err != nil {
+		return err
+	}
+
+	return project(to, from, child(head, mid), tail)
+}
+
+func projectSlice(dst, src reflect.Value, head P, mid string, tail P) error {
+	if src.Kind() == reflect.Slice && dst.Len() < src.Len() {
+		expanded := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		reflect.Copy(expanded, dst)
+		dst.Set(expanded)
+	}
+
+	if mid == "*" {
+		for i := 0; i < src.Len(); i++ {
+			if err := project(dst.Index(i), src.Index(i), child(head, strconv.Itoa(i)), tail); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	index, err := strconv.ParseInt(mid, 10, 32)
+	if err != nil || index < 0 {
+		return fmt.Errorf("invalid index '%s' at '%s'", mid, head)
+	}
+
+	if int(index) >= src.Len() {
+		return nil
+	}
+
+	return project(dst.Index(int(index)), src.Index(int(index)), child(head, mid), tail)
+}
+
+func projectMap(dst, src reflect.Value, head P, mid string, tail P) error {
+	typ := src.Type()
+
+	if key := typ.Key(); key.Kind() != reflect.String {
+		return fmt.Errorf("unsupported key type '%s' for map '%s' at '%s'", key, mid, head)
+	}
+
+	keys := []reflect.Value{reflect.ValueOf(mid).Convert(typ.Key())}
+	if mid == "*" {
+		keys = src.MapKeys()
+	}
+
+	for _, key := range keys {
+		from := src.MapIndex(key)
+		if !from.IsValid() {
+			continue
+		}
+
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMap(typ))
+		}
+
+		value := reflect.New(typ.Elem()).Elem()
+		if to := dst.MapIndex(key); to.IsValid() {
+			value.Set(to)
+		}
+
+		if err := project(value, from, child(head, key.String()), tail); err != nil {
+			return err
+		}
+
+		dst.SetMapIndex(key, value)
+	}
+
+	return nil
+}
+
+// insertPath adds the value to the nested maps at the given path. Values that
+// were already selected as a whole are left untouched.
+func insertPath(result map[string]interface{}, path P, value interface{}) {
+	if len(path) == 0 {
+		return
+	}
+
+	for _, item := range path[:len(path)-1] {
+		next, ok := result[item].(map[string]interface{})
+		if !ok {
+			if _, exists := result[item]; exists {
+				return
+			}
+
+			next = map[string]interface{}{}
+			result[item] = next
+		}
+
+		result = next
+	}
+
+	result[path.Last()] = value
+}
+
+// copyOf returns a deep copy of the value or nil if it can't be accessed.
+func copyOf(value reflect.Value) interface{} {
+	if !value.CanInterface() {
+		return nil
+	}
+	return interfaceOf(deepCopy(reflect.ValueOf(value.Interface())))
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+	"testing"
+)
+
+type projectAddress struct {
+	City string
+	Zip  string
+}
+
+type projectUser struct {
+	projectAddress
+
+	Name    string
+	Email   string
+	Age     int
+	Home    *projectAddress
+	Tags    []string
+	Friends []projectUser
+	Meta    map[string]projectAddress
+	Any     interface{}
+	secret  string
+}
+
+func (u projectUser) Upper() string { return u.Name + "!" }
+
+func newProjectUser() *projectUser {
+	return &projectUser{
+		projectAddress: projectAddress{City: "a", Zip: "1"},
+
+		Name:  "bob",
+		Email: "bob@example.com",
+		Age:   42,
+		Home:  &projectAddress{City: "b", Zip: "2"},
+		Tags:  []string{"x", "y"},
+		Friends: []projectUser{
+			{Name: "alice", Age: 1},
+			{Name: "eve", Age: 2},
+		},
+		Meta:   map[string]projectAddress{"work": {City: "c", Zip: "3"}, "other": {City: "d"}},
+		Any:    &projectAddress{City: "e", Zip: "5"},
+		secret: "s",
+	}
+}
+
+func TestProject(t *testing.T) {
+	obj := newProjectUser()
+
+	checkProject(t, obj, []string{"Name", "Age"}, &projectUser{Name: "bob", Age: 42})
+	checkProject(t, obj, []string{"Home.City"}, &projectUser{Home: &projectAddress{City: "b"}})
+	checkProject(t, obj, []string{"City"}, &projectUser{projectAddress: projectAddress{City: "a"}})
+	checkProject(t, obj, []string{"Tags.1"}, &projectUser{Tags: []string{"", "y"}})
+	checkProject(t, obj, []string{"Tags.5"}, &projectUser{Tags: []string{"", ""}})
+
+	checkProject(t, obj, []string{"Friends.*.Name", "Name"}, &projectUser{
+		Name:    "bob",
+		Friends: []projectUser{{Name: "alice"}, {Name: "eve"}},
+	})
+
+	checkProject(t, obj, []string{"Meta.*.City", "Meta.work.Zip"}, &projectUser{
+		Meta: map[string]projectAddress{"work": {City: "c", Zip: "3"}, "other": {City: "d"}},
+	})
+
+	checkProject(t, obj, []string{"Meta.nope.City"}, &projectUser{})
+	checkProject(t, obj, []string{"Any.Zip"}, &projectUser{Any: &projectAddress{Zip: "5"}})
+
+	// Selected values are copies of the original.
+	result, err := Project(obj, New("Home"), New("Tags"))
+	if err != nil {
+		t.Fatalf("FAIL: Project(Home, Tags) -> %s", err)
+	}
+
+	if user := result.(*projectUser); user.Home == obj.Home || &user.Tags[0] == &obj.Tags[0] {
+		t.Errorf("FAIL: Project(Home, Tags) -> shares values with the object")
+	}
+
+	projectFail(t, obj, "Unknown")
+	projectFail(t, obj, "secret")
+	projectFail(t, obj, "Upper()")
+	projectFail(t, obj, "Tags.x")
+	projectFail(t, obj, "Name.First")
+
+	if _, err := Project(nil, New("Name")); err != ErrNil {
+		t.Errorf("FAIL: Project(nil) -> %v != %v", err, ErrNil)
+	}
+}
+
+func TestProjectMap(t *testing.T) {
+	obj := newProjectUser()
+
+	result, err := ProjectMap(obj,
+		New("Name"), New("Home.City"), New("Friends.*.Name"), New("Meta.work"),
+		New("Upper()"), New("Home"), New("Meta.nope"))
+
+	if err != nil {
+		t.Fatalf("FAIL: ProjectMap -> %s", err)
+	}
+
+	exp := map[string]interface{}{
+		"Name":    "bob",
+		"Home":    &projectAddress{City: "b", Zip: "2"},
+		"Friends": map[string]interface{}{"0": map[string]interface{}{"Name": "alice"}, "1": map[string]interface{}{"Name": "eve"}},
+		"Meta":    map[string]interface{}{"work": projectAddress{City: "c", Zip: "3"}},
+		"Upper()": "bob!",
+	}
+
+	if !reflect.DeepEqual(result, exp) {
+		t.Errorf("FAIL: ProjectMap -> %v != %v", result, exp)
+	}
+
+	if result["Home"] == obj.Home {
+		t.Errorf("FAIL: ProjectMap -> shares values with the object")
+	}
+
+	if _, err := ProjectMap(obj, New("Unknown")); err == nil {
+		t.Errorf("FAIL: ProjectMap(Unknown) -> expected failure")
+	}
+}
+
+func checkProject(t *testing.T, obj interface{}, paths []string, exp interface{}) {
+	var projection []P
+	for _, path := range paths {
+		projection = append(projection, New(path))
+	}
+
+	if result, err := Project(obj, projection...); err != nil {
+		t.Errorf("FAIL(%v): Project -> %s", paths, err)
+
+	} else if !reflect.DeepEqual(result, exp) {
+		t.Errorf("FAIL(%v): Project -> %+v != %+v", paths, result, exp)
+	}
+}
+
+func projectFail(t *testing.T, obj interface{}, path string) {
+	if _, err := Project(obj, New(path)); err == nil {
+		t.Errorf("FAIL(%s): Project -> expected failure", path)
+	}
+}
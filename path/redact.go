@@ -0,0 +1,196 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// RedactMask is the value that replaces the redacted strings by default.
+const RedactMask = "[REDACTED]"
+
+// RedactTag is the struct tag which marks a field to be redacted when it is set
+// to 'true' (e.g. `redact:"true"`).
+const RedactTag = "redact"
+
+// RedactOptions controls how Redact masks values.
+type RedactOptions struct {
+
+	// Mask is the value that replaces the redacted strings. RedactMask is used
+	// if it is empty.
+	Mask string
+
+	// Zero zeroes the redacted strings instead of replacing them by Mask.
+	Zero bool
+}
+
+// Redact returns a deep copy of the object where the values matching the given
+// path patterns, or held by fields tagged with `redact:"true"`, are redacted.
+// Patterns can contain '*' components, which match any component, and '**'
+// components, which match any number of components such that '**.Password'
+// matches a Password field anywhere in the object. Redacted strings are
+// replaced by the mask of opts, pointers and interfaces have the value they
+// point to redacted and any other value is zeroed. Note that the unexported
+// fields of structs, except for embedded structs, are shared with the original
+// and are never redacted.
+func Redact(obj interface{}, opts RedactOptions, patterns ...P) (interface{}, error) {
+	if obj == nil {
+		return nil, ErrNil
+	}
+
+	result := reflect.New(reflect.TypeOf(obj)).Elem()
+	result.Set(deepCopy(reflect.ValueOf(obj)))
+
+	r := &redactor{patterns: patterns, mask: opts.Mask, seen: map[copyKey]bool{}}
+	if opts.Zero {
+		r.mask = ""
+	} else if r.mask == "" {
+		r.mask = RedactMask
+	}
+
+	r.redact(result, P{})
+
+	return result.Interface(), nil
+}
+
+type redactor struct {
+	patterns []P
+	mask     string
+	seen     map[copyKey]bool
+}
+
+func (r *redactor) match(path P) bool {
+	for _, pattern := range r.patterns {
+		if matchPath(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact walks the value and masks the values matching the patterns or tagged
+// for redaction. Values that can't be set, such as unexported fields, are
+// skipped.
+func (r *redactor) redact(value reflect.Value, path P) {
+	if !value.CanSet() {
+		return
+	}
+
+	if len(path) > 0 && r.match(path) {
+		r.maskValue(value)
+		return
+	}
+
+	switch value.Kind() {
+
+	case reflect.Ptr, reflect.Map, reflect.Slice:
+		if value.IsNil() || value.Kind() == reflect.Slice && value.Len() == 0 {
+			return
+		}
+
+		// The copy preserves the cycles of the original.
+		key := copyKey{value.Pointer(), value.Type()}
+		if r.seen[key] {
+			return
+		}
+		r.seen[key] = true
+		defer delete(r.seen, key)
+
+		r.redactElems(value, path)
+
+	case reflect.Interface:
+		if value.IsNil() {
+			return
+		}
+
+		elem := reflect.New(value.Elem().Type()).Elem()
+		elem.Set(value.Elem())
+		r.redact(elem, path)
+		value.Set(elem)
+
+	case reflect.Struct:
+		typ := value.Type()
+
+		for i := 0; i < typ.NumField(); i++ {
+			field, elem := typ.Field(i), value.Field(i)
+
+			if field.PkgPath != "" {
+				if !field.Anonymous {
+					continue
+				}
+
+				// The fields promoted from unexported embedded structs are
+				// visible to encoders but can't be set through reflection and
+				// are shared with the original by the copy. They're copied and
+				// redacted through their address within our copy instead.
+				elem = reflect.NewAt(field.Type, unsafe.Pointer(elem.UnsafeAddr())).Elem()
+				elem.Set(deepCopy(elem))
+			}
+
+			if field.Tag.Get(RedactTag) == "true" {
+				r.maskValue(elem)
+				continue
+			}
+
+			r.redact(elem, child(path, field.Name))
+		}
+
+	case reflect.Array:
+		r.redactElems(value, path)
+	}
+}
+
+// redactElems redacts the value pointed to by a pointer or the elements of an
+// array, slice or map.
+func (r *redactor) redactElems(value reflect.Value, path P) {
+	switch value.Kind() {
+
+	case reflect.Ptr:
+		r.redact(value.Elem(), path)
+
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			r.redact(value.Index(i), child(path, fmt.Sprint(i)))
+		}
+
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			elem := reflect.New(value.Type().Elem()).Elem()
+			elem.Set(value.MapIndex(key))
+			r.redact(elem, child(path, fmt.Sprint(key.Interface())))
+			value.SetMapIndex(key, elem)
+		}
+	}
+}
+
+// maskValue replaces the value by the mask if it is a string and zeroes it
+// otherwise. The values of pointers and interfaces are masked.
+func (r *redactor) maskValue(value reflect.Value) {
+	if !value.CanSet() {
+		return
+	}
+
+	switch value.Kind() {
+
+	case reflect.String:
+		value.SetString(r.mask)
+
+	case reflect.Ptr:
+		if !value.IsNil() {
+			r.maskValue(value.Elem())
+		}
+
+	case reflect.Interface:
+		if !value.IsNil() {
+			elem := reflect.New(value.Elem().Type()).Elem()
+			elem.Set(value.Elem())
+			r.maskValue(elem)
+			value.Set(elem)
+		}
+
+	default:
+		value.Set(reflect.Zero(value.Type()))
+	}
+}
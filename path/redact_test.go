@@ -0,0 +1,200 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+	"testing"
+)
+
+type redactCard struct {
+	Number string
+	CVV    int
+	Owner  *string
+}
+
+type redactRequest struct {
+	User     string
+	Email    string `redact:"true"`
+	Password string
+	Token    *string
+	Cards    []redactCard
+	Headers  map[string]string
+	Extra    map[string]interface{}
+	Nested   *redactRequest
+	Tags     []string
+	PIN      int `redact:"true"`
+	hidden   string
+}
+
+func TestRedact(t *testing.T) {
+	token, owner := "abc", "bob"
+
+	obj := &redactRequest{
+		User:     "bob",
+		Email:    "bob@example.com",
+		Password: "hunter2",
+		Token:    &token,
+		Cards:    []redactCard{{Number: "4111", CVV: 123, Owner: &owner}, {Number: "5500", CVV: 456}},
+		Headers:  map[string]string{"Authorization": "Bearer abc", "Accept": "*/*"},
+		Extra:    map[string]interface{}{"password": "x", "token": &token, "n": 1},
+		Nested:   &redactRequest{User: "eve", Password: "pass", Email: "eve@example.com"},
+		Tags:     []string{"a", "b"},
+		PIN:      1234,
+		hidden:   "h",
+	}
+
+	obj.Nested.Nested = obj
+
+	result, err := Redact(obj, RedactOptions{},
+		New("**.Password"), New("Token"), New("Cards.*.Number"), New("Cards.*.CVV"),
+		New("Cards.*.Owner"), New("Headers.Authorization"), New("Extra.*"), New("Tags"))
+
+	if err != nil {
+		t.Fatalf("FAIL: Redact -> %s", err)
+	}
+
+	r := result.(*redactRequest)
+
+	checkRedact(t, "User", r.User, "bob")
+	checkRedact(t, "Email", r.Email, RedactMask)
+	checkRedact(t, "Password", r.Password, RedactMask)
+	checkRedact(t, "Token", *r.Token, RedactMask)
+	checkRedact(t, "Cards.0.Number", r.Cards[0].Number, RedactMask)
+	checkRedact(t, "Cards.1.Number", r.Cards[1].Number, RedactMask)
+	checkRedact(t, "Cards.0.CVV", r.Cards[0].CVV, 0)
+	checkRedact(t, "Cards.0.Owner", *r.Cards[0].Owner, RedactMask)
+	checkRedact(t, "Headers.Authorization", r.Headers["Authorization"], RedactMask)
+	checkRedact(t, "Headers.Accept", r.Headers["Accept"], "*/*")
+	checkRedact(t, "Extra.password", r.Extra["password"], RedactMask)
+	checkRedact(t, "Extra.token", *r.Extra["token"].(*string), RedactMask)
+	checkRedact(t, "Extra.n", r.Extra["n"], 0)
+	checkRedact(t, "Nested.User", r.Nested.User, "eve")
+	checkRedact(t, "Nested.Password", r.Nested.Password, RedactMask)
+	checkRedact(t, "Nested.Email", r.Nested.Email, RedactMask)
+	checkRedact(t, "Tags", r.Tags, []string(nil))
+	checkRedact(t, "PIN", r.PIN, 0)
+	checkRedact(t, "hidden", r.hidden, "h")
+
+	if r.Nested.Nested != r {
+		t.Errorf("FAIL: Redact -> cycle not preserved")
+	}
+
+	// The original is left untouched.
+	checkRedact(t, "original", obj.Password, "hunter2")
+	checkRedact(t, "original", token, "abc")
+	checkRedact(t, "original", owner, "bob")
+	checkRedact(t, "original", obj.Extra["password"], "x")
+	checkRedact(t, "original", obj.Nested.Email, "eve@example.com")
+
+	if _, err := Redact(nil, RedactOptions{}); err != ErrNil {
+		t.Errorf("FAIL: Redact(nil) -> %v != %v", err, ErrNil)
+	}
+}
+
+func TestRedactValue(t *testing.T) {
+	result, err := Redact(map[string]interface{}{
+		"user": map[string]interface{}{"password": "x", "name": "bob"},
+		"list": []interface{}{map[string]interface{}{"password": "y"}},
+	}, RedactOptions{}, New("**.password"))
+
+	if err != nil {
+		t.Fatalf("FAIL: Redact -> %s", err)
+	}
+
+	exp := map[string]interface{}{
+		"user": map[string]interface{}{"password": RedactMask, "name": "bob"},
+		"list": []interface{}{map[string]interface{}{"password": RedactMask}},
+	}
+
+	if !reflect.DeepEqual(result, exp) {
+		t.Errorf("FAIL: Redact -> %v != %v", result, exp)
+	}
+}
+
+type redactInner struct {
+	Password string
+	Tokens   []string
+}
+
+type redactOuter struct {
+	redactInner
+	*redactRequest
+	User string
+}
+
+func TestRedactUnexportedEmbedded(t *testing.T) {
+	obj := redactOuter{
+		redactInner:   redactInner{Password: "secret", Tokens: []string{"a"}},
+		redactRequest: &redactRequest{Password: "hunter2", Email: "bob@example.com"},
+		User:          "bob",
+	}
+
+	result, err := Redact(obj, RedactOptions{}, New("**.Password"), New("**.Tokens.*"))
+	if err != nil {
+		t.Fatalf("FAIL: Redact -> %s", err)
+	}
+
+	r := result.(redactOuter)
+	checkRedact(t, "redactInner.Password", r.redactInner.Password, RedactMask)
+	checkRedact(t, "redactInner.Tokens", r.redactInner.Tokens, []string{RedactMask})
+	checkRedact(t, "redactRequest.Password", r.redactRequest.Password, RedactMask)
+	checkRedact(t, "redactRequest.Email", r.redactRequest.Email, RedactMask)
+	checkRedact(t, "User", r.User, "bob")
+
+	checkRedact(t, "original", obj.redactInner.Password, "secret")
+	checkRedact(t, "original", obj.redactInner.Tokens[0], "a")
+	checkRedact(t, "original", obj.redactRequest.Password, "hunter2")
+	checkRedact(t, "original", obj.redactRequest.Email, "bob@example.com")
+}
+
+func TestRedactOptions(t *testing.T) {
+	obj := &redactRequest{User: "bob", Password: "hunter2", Email: "bob@example.com"}
+
+	result, err := Redact(obj, RedactOptions{Mask: "***"}, New("Password"))
+	if err != nil {
+		t.Fatalf("FAIL: Redact -> %s", err)
+	}
+
+	checkRedact(t, "Password", result.(*redactRequest).Password, "***")
+	checkRedact(t, "Email", result.(*redactRequest).Email, "***")
+
+	result, err = Redact(obj, RedactOptions{Mask: "***", Zero: true}, New("Password"))
+	if err != nil {
+		t.Fatalf("FAIL: Redact -> %s", err)
+	}
+
+	checkRedact(t, "Password", result.(*redactRequest).Password, "")
+	checkRedact(t, "Email", result.(*redactRequest).Email, "")
+	checkRedact(t, "User", result.(*redactRequest).User, "bob")
+}
+
+func TestRedactCycle(t *testing.T) {
+	obj := map[string]interface{}{"a": "x"}
+	obj["self"] = obj
+
+	list := []interface{}{"y", nil}
+	list[1] = list
+	obj["list"] = list
+
+	result, err := Redact(obj, RedactOptions{}, New("a"), New("list.0"))
+	if err != nil {
+		t.Fatalf("FAIL: Redact -> %s", err)
+	}
+
+	r := result.(map[string]interface{})
+	checkRedact(t, "a", r["a"], RedactMask)
+	checkRedact(t, "list.0", r["list"].([]interface{})[0], RedactMask)
+	checkRedact(t, "original", obj["a"], "x")
+	checkRedact(t, "original", list[0], "y")
+
+	if reflect.ValueOf(r["self"]).Pointer() != reflect.ValueOf(r).Pointer() {
+		t.Errorf("FAIL: Redact -> cycle not preserved")
+	}
+}
+
+func checkRedact(t *testing.T, title string, value, exp interface{}) {
+	if !reflect.DeepEqual(value, exp) {
+		t.Errorf("FAIL(%s): Redact -> %v != %v", title, value, exp)
+	}
+}
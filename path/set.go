@@ -13,11 +13,7 @@ import (
 // object. Returns an error if the object is not addresable and therefore not
 // modifiable.
 func (path P) Set(obj, value interface{}) error {
-	fn := func(p P, ctx *Context) (bool, error) {
-		return false, set(p, ctx, reflect.ValueOf(value))
-	}
-
-	return path.Apply(obj, &Context{CreateIfMissing: true, Fn: fn})
+	return path.Apply(obj, setContext(value, false))
 }
 
 // SetAll modifies all the values in the given object that matches the path to
@@ -26,24 +22,46 @@ func (path P) Set(obj, value interface{}) error {
 // object. Returns an error if the object is not addresable and therefore not
 // modifiable.
 func (path P) SetAll(obj, value interface{}) (err error) {
+	return path.Apply(obj, setContext(value, true))
+}
+
+// setContext returns a context which sets the given value on the first match or
+// on all the matches if all is true.
+func setContext(value interface{}, all bool) *Context {
 	fn := func(p P, ctx *Context) (bool, error) {
-		return true, set(p, ctx, reflect.ValueOf(value))
+		return all, set(p, ctx, reflect.ValueOf(value))
 	}
 
-	return path.Apply(obj, &Context{CreateIfMissing: true, Fn: fn})
+	return &Context{CreateIfMissing: true, Fn: fn}
 }
 
 func set(path P, ctx *Context, value reflect.Value) error {
 	obj := ctx.Value()
 
+	if setter, ok := implements(ctx.Parent(), pathSetterType).(PathSetter); ok {
+		if ctx.DryRun {
+			ctx.record(path, reflect.Value{}, value, false)
+			return nil
+		}
+		return setter.PathSet(path.Last(), value.Interface())
+	}
+
 	if obj.Kind() == reflect.Chan {
 		if obj.Type().Elem() == value.Type() {
+			if ctx.DryRun {
+				ctx.record(path, reflect.Value{}, value, false)
+				return nil
+			}
 			obj.Send(value)
 			return nil
 		}
 	}
 
 	if isSetterFor(obj, value) {
+		if ctx.DryRun {
+			ctx.record(path, reflect.Value{}, value, false)
+			return nil
+		}
 		return callSetter(obj, value)
 	}
 
@@ -56,12 +74,12 @@ func set(path P, ctx *Context, value reflect.Value) error {
 	}
 
 	if obj.CanSet() {
-		obj.Set(value)
+		ctx.setValue(path, obj, value, false)
 		return nil
 	}
 
 	if parent := ctx.Parent(); parent.Kind() == reflect.Map {
-		parent.SetMapIndex(reflect.ValueOf(path.Last()), value)
+		ctx.setMapIndex(path, parent, reflect.ValueOf(path.Last()), value, false)
 		return nil
 	}
 
@@ -0,0 +1,224 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// tagFormat describes the naming rules followed by the encoders that use a
+// given struct tag.
+type tagFormat struct {
+
+	// lower indicates that untagged fields are named using their lowercased
+	// field name.
+	lower bool
+
+	// inline indicates that the ',inline' option flattens the fields of a
+	// struct into its parent.
+	inline bool
+
+	// embed indicates that untagged embedded structs have their fields
+	// promoted into their parent.
+	embed bool
+
+	// nested indicates that names of the form 'a>b' denote nested elements.
+	nested bool
+
+	// xmlName indicates that the XMLName field holds the name of the element
+	// itself rather than the name of a child element.
+	xmlName bool
+
+	// strict indicates that names containing invalid characters are ignored
+	// in favour of the field name.
+	strict bool
+}
+
+var tagFormats = map[string]tagFormat{
+	"json":    {embed: true, strict: true},
+	"yaml":    {lower: true, inline: true},
+	"bson":    {lower: true, inline: true},
+	"xml":     {embed: true, nested: true, xmlName: true},
+	"toml":    {embed: true},
+	"msgpack": {embed: true, inline: true},
+}
+
+// defaultTagFormat is used for tags that are not known.
+var defaultTagFormat = tagFormat{embed: true, inline: true}
+
+func getTagFormat(tag string) tagFormat {
+	if format, ok := tagFormats[tag]; ok {
+		return format
+	}
+	return defaultTagFormat
+}
+
+// tagField associates the names of a field within a tag namespace to the path
+// of the field within its struct.
+type tagField struct {
+	names  P
+	path   P
+	typ    reflect.Type
+	depth  int
+	tagged bool
+	opts   string
+	field  reflect.StructField
+}
+
+// tagFields returns the fields of the given struct type as named by the given
+// tag. The fields of inlined and embedded structs are included and conflicts
+// are resolved using the rules of encoding/json: the least nested field wins
+// and, amongst fields at the same depth, a single tagged field wins. Otherwise
+// all the conflicting fields are dropped.
+func tagFields(typ reflect.Type, tag string) []tagField {
+	var fields []tagField
+	collectTagFields(typ, getTagFormat(tag), tag, P{}, 0, map[reflect.Type]bool{}, &fields)
+
+	groups := make(map[string][]int)
+	for i, field := range fields {
+		name := field.names.String()
+		groups[name] = append(groups[name], i)
+	}
+
+	var result []tagField
+	for i, field := range fields {
+		if dominantTagField(fields, groups[field.names.String()]) == i {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+// dominantTagField returns the index of the field that wins amongst the fields
+// sharing the same name or -1 if there are no winners.
+func dominantTagField(fields []tagField, group []int) int {
+	result, conflict := group[0], false
+
+	for _, i := range group[1:] {
+		field, best := fields[i], fields[result]
+
+		switch {
+		case field.depth > best.depth:
+		case field.depth < best.depth:
+			result, conflict = i, false
+		case field.tagged && !best.tagged:
+			result, conflict = i, false
+		case field.tagged == best.tagged:
+			conflict = true
+		}
+	}
+
+	if conflict {
+		return -1
+	}
+	return result
+}
+
+func collectTagFields(
+	typ reflect.Type, format tagFormat, tag string,
+	path P, depth int, visited map[reflect.Type]bool, fields *[]tagField) {
+
+	if visited[typ] {
+		return
+	}
+
+	visited[typ] = true
+	defer delete(visited, typ)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		inner := indirectType(field.Type)
+
+		// Embedded structs are kept even if they're not exported since their
+		// fields may be exported.
+		if field.PkgPath != "" && (!field.Anonymous || inner.Kind() != reflect.Struct) {
+			continue
+		}
+
+		value := field.Tag.Get(tag)
+		if value == "-" || (format.xmlName && field.Name == "XMLName") {
+			continue
+		}
+
+		name, opts := value, ""
+		if j := strings.Index(value, ","); j >= 0 {
+			name, opts = value[:j], value[j+1:]
+		}
+
+		if format.strict && !isValidTagName(name) {
+			name = ""
+		}
+
+		fieldPath := append(append(P{}, path...), field.Name)
+
+		if inner.Kind() == reflect.Struct {
+			inline := format.inline && hasTagOption(opts, "inline")
+			embed := format.embed && field.Anonymous && name == ""
+
+			if inline || embed {
+				collectTagFields(inner, format, tag, fieldPath, depth+1, visited, fields)
+				continue
+			}
+		}
+
+		if format.nested {
+			if hasTagOption(opts, "chardata") || hasTagOption(opts, "innerxml") ||
+				hasTagOption(opts, "comment") || hasTagOption(opts, "any") {
+				continue
+			}
+
+			// Namespaced names are of the form 'namespace local'.
+			if j := strings.LastIndex(name, " "); j >= 0 {
+				name = name[j+1:]
+			}
+		}
+
+		tagged := name != ""
+
+		if name == "" {
+			name = field.Name
+			if format.lower {
+				name = strings.ToLower(name)
+			}
+		}
+
+		names := P{name}
+		if format.nested {
+			names = strings.Split(name, ">")
+		}
+
+		*fields = append(*fields, tagField{names, fieldPath, field.Type, depth, tagged, opts, field})
+	}
+}
+
+func hasTagOption(opts, option string) bool {
+	for _, item := range strings.Split(opts, ",") {
+		if item == option {
+			return true
+		}
+	}
+	return false
+}
+
+func indirectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// isValidTagName mirrors the validation of names done by encoding/json.
+func isValidTagName(name string) bool {
+	for _, c := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
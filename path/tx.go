@@ -0,0 +1,103 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+)
+
+// Tx records the modifications made to objects by the paths applied through it
+// such that they can be rolled back. This includes values that were
+// overwritten, map keys that were created and slices that were expanded. Note
+// that values sent on channels and values written through setter functions or
+// the PathSetter interface can't be rolled back. A Tx is not safe for
+// concurrent use.
+type Tx struct {
+	undo []func()
+}
+
+// Apply applies the given context to the object using the path and records any
+// modifications in the transaction. If an error is returned then the
+// modifications made by this call are rolled back.
+func (tx *Tx) Apply(path P, obj interface{}, ctx *Context) error {
+	defer func(prev *Tx) { ctx.tx = prev }(ctx.tx)
+
+	n := len(tx.undo)
+
+	ctx.tx = tx
+	err := path.Apply(obj, ctx)
+
+	if err != nil {
+		tx.rollback(n)
+	}
+
+	return err
+}
+
+// Set is similar to P.Set but records the modifications in the transaction.
+func (tx *Tx) Set(path P, obj, value interface{}) error {
+	return tx.Apply(path, obj, setContext(value, false))
+}
+
+// SetAll is similar to P.SetAll but records the modifications in the
+// transaction. If setting any of the values fails then all the values set by
+// this call are rolled back.
+func (tx *Tx) SetAll(path P, obj, value interface{}) error {
+	return tx.Apply(path, obj, setContext(value, true))
+}
+
+// Rollback reverts all the modifications recorded by the transaction in the
+// reverse order in which they were made.
+func (tx *Tx) Rollback() {
+	tx.rollback(0)
+}
+
+// Commit discards the modifications recorded by the transaction which means
+// that they can no longer be rolled back.
+func (tx *Tx) Commit() {
+	tx.undo = nil
+}
+
+func (tx *Tx) rollback(n int) {
+	for i := len(tx.undo) - 1; i >= n; i-- {
+		tx.undo[i]()
+	}
+	tx.undo = tx.undo[:n]
+}
+
+// setValue sets the value of obj and records its previous value if a
+// transaction is associated with the context. In dry run mode the change is
+// only recorded and obj is left untouched. Returns the value that now holds the
+// new value which is value itself in dry run mode.
+func (ctx *Context) setValue(path P, obj, value reflect.Value, created bool) reflect.Value {
+	if ctx.DryRun {
+		ctx.record(path, obj, value, created)
+		return value
+	}
+
+	if ctx.tx != nil {
+		old := reflect.New(obj.Type()).Elem()
+		old.Set(obj)
+		ctx.tx.undo = append(ctx.tx.undo, func() { obj.Set(old) })
+	}
+
+	obj.Set(value)
+	return obj
+}
+
+// setMapIndex sets the value of the key in the map and records its previous
+// value, or its absence, if a transaction is associated with the context. In
+// dry run mode the change is only recorded and the map is left untouched.
+func (ctx *Context) setMapIndex(path P, obj, key, value reflect.Value, created bool) {
+	if ctx.DryRun {
+		ctx.record(path, obj.MapIndex(key), value, created)
+		return
+	}
+
+	if ctx.tx != nil {
+		old := obj.MapIndex(key)
+		ctx.tx.undo = append(ctx.tx.undo, func() { obj.SetMapIndex(key, old) })
+	}
+
+	obj.SetMapIndex(key, value)
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTx(t *testing.T) {
+	type Item struct {
+		A  int
+		PA *int
+	}
+
+	obj := &struct {
+		I  int
+		M  map[string]int
+		MM map[string]map[string][]int
+		S  []Item
+		P  *Item
+		V  map[string]interface{}
+		L  []interface{}
+	}{
+		I: 1,
+		M: map[string]int{"x": 1},
+		S: []Item{{A: 1}, {A: 2}},
+		V: map[string]interface{}{"a": 1, "b": "str"},
+		L: []interface{}{&Item{A: 1}, 2},
+	}
+
+	snapshot := func() interface{} {
+		result := *obj
+		result.M = map[string]int{}
+		for key, value := range obj.M {
+			result.M[key] = value
+		}
+		result.S = append([]Item(nil), obj.S...)
+		result.V = map[string]interface{}{}
+		for key, value := range obj.V {
+			result.V[key] = value
+		}
+		return result
+	}
+
+	before := snapshot()
+
+	tx := new(Tx)
+	txSet(t, tx, "I", obj, 2)
+	txSet(t, tx, "M.x", obj, 2)
+	txSet(t, tx, "M.y", obj, 3)
+	txSet(t, tx, "MM.a.b.3", obj, 4)
+	txSet(t, tx, "S.4.A", obj, 5)
+	txSet(t, tx, "S.0.PA", obj, intPtr(6))
+	txSet(t, tx, "P.A", obj, 7)
+
+	if err := tx.SetAll(New("S.*.A"), obj, 8); err != nil {
+		t.Errorf("FAIL: SetAll(S.*.A) -> %s", err)
+	}
+
+	tx.Rollback()
+
+	if after := snapshot(); !reflect.DeepEqual(before, after) || obj.MM != nil {
+		t.Errorf("FAIL: rollback -> %+v != %+v", after, before)
+	}
+
+	// A failure while setting the values rolls back the values that were
+	// already set by the call.
+	if err := tx.SetAll(New("L.*.A"), obj, 10); err == nil {
+		t.Errorf("FAIL: SetAll(L.*.A) -> expected failure")
+	}
+
+	if value := obj.L[0].(*Item).A; value != 1 {
+		t.Errorf("FAIL: failed SetAll -> %d != 1", value)
+	}
+
+	txSet(t, tx, "I", obj, 11)
+	tx.Commit()
+	tx.Rollback()
+
+	if obj.I != 11 {
+		t.Errorf("FAIL: commit -> %d != 11", obj.I)
+	}
+
+	// The context doesn't keep recording into the Tx after the call.
+	ctx := setContext(12, false)
+	if err := tx.Apply(New("I"), obj, ctx); err != nil {
+		t.Errorf("FAIL: Apply(I) -> %s", err)
+	}
+
+	tx.Commit()
+	if err := New("I").Apply(obj, ctx); err != nil {
+		t.Errorf("FAIL: reused context -> %s", err)
+	}
+
+	if len(tx.undo) != 0 {
+		t.Errorf("FAIL: reused context -> recorded in tx")
+	}
+}
+
+func txSet(t *testing.T, tx *Tx, path string, obj, value interface{}) {
+	if err := tx.Set(New(path), obj, value); err != nil {
+		t.Errorf("FAIL: set %s -> %s", path, err)
+	}
+}
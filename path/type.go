@@ -3,7 +3,9 @@
 package path
 
 import (
+	"fmt"
 	"reflect"
+	"strconv"
 )
 
 // Type returns the type of the first value in the given object that matches the
@@ -18,3 +20,184 @@ func (path P) Type(obj interface{}) (result reflect.Type, err error) {
 	err = path.Apply(obj, &Context{Fn: fn})
 	return
 }
+
+// TypeOf returns the static type of the values that would be reached by
+// applying the path to a value of the given type. Pointers, slices, arrays,
+// maps and channels are traversed through their element type while functions
+// and methods are traversed through the type of their first return value. The
+// components of the path are validated along the way. Returns
+// ErrUnresolvable if the type can only be determined from an actual value
+// which is the case for interfaces, PathGetter implementations and wildcards
+// on structs whose fields have different types.
+func TypeOf(typ reflect.Type, path P) (reflect.Type, error) {
+	return typeOf(typ, P{}, path)
+}
+
+func typeOf(typ reflect.Type, head, tail P) (reflect.Type, error) {
+	for len(tail) > 0 {
+		mid := tail[0]
+
+		if typ.Implements(pathGetterType) || reflect.PtrTo(typ).Implements(pathGetterType) {
+			return nil, ErrUnresolvable
+		}
+
+		name, args, call := splitCall(mid)
+		if !call {
+			name = mid
+		}
+
+		if method, ok := staticMethod(typ, name); ok {
+			if typ = method; call {
+				result, err := typeOfCall(method, head, mid, args)
+				if err != nil {
+					return nil, err
+				}
+				typ = result
+			}
+
+			head, tail = append(head, mid), tail[1:]
+			continue
+		}
+
+		switch typ.Kind() {
+
+		case reflect.Ptr:
+			// Pointers are dereferenced without consuming a component.
+			typ = typ.Elem()
+			continue
+
+		case reflect.Interface:
+			return nil, ErrUnresolvable
+
+		case reflect.Struct:
+			if call {
+				return nil, fmt.Errorf("no method '%s' in type '%s' at '%s'", name, typ, head)
+			}
+
+			if mid == "*" {
+				result, err := typeOfFields(typ, head)
+				if err != nil {
+					return nil, err
+				}
+				typ = result
+				break
+			}
+
+			field, ok := typ.FieldByName(mid)
+			if !ok {
+				return nil, fmt.Errorf("no field '%s' in type '%s' at '%s'", mid, typ, head)
+			}
+			typ = field.Type
+
+		case reflect.Array, reflect.Slice:
+			if mid != "*" {
+				index, err := strconv.ParseInt(mid, 10, 32)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("invalid index '%s' at '%s'", mid, head)
+				}
+
+				if typ.Kind() == reflect.Array && int(index) >= typ.Len() {
+					return nil, fmt.Errorf("index %d out of range for type '%s' at '%s'", index, typ, head)
+				}
+			}
+			typ = typ.Elem()
+
+		case reflect.Map:
+			if key := typ.Key(); key.Kind() != reflect.String {
+				return nil, fmt.Errorf("unsupported key type '%s' for map '%s' at '%s'", key, mid, head)
+			}
+			typ = typ.Elem()
+
+		case reflect.Chan:
+			if dir := typ.ChanDir(); dir != reflect.RecvDir && dir != reflect.BothDir {
+				return nil, fmt.Errorf("invalid channel direction '%s' at '%s'", dir, head)
+			}
+
+			if !isChanComponent(mid) {
+				return nil, fmt.Errorf("invalid channel component '%s' at '%s'", mid, head)
+			}
+			typ = typ.Elem()
+
+		case reflect.Func:
+			if !call || name != "" {
+				return nil, fmt.Errorf("missing required '()' pathing component at '%s'", head)
+			}
+
+			result, err := typeOfCall(typ, head, mid, args)
+			if err != nil {
+				return nil, err
+			}
+			typ = result
+
+		default:
+			return nil, fmt.Errorf("invalid kind '%s' in at '%s'", typ.Kind(), head)
+		}
+
+		head, tail = append(head, mid), tail[1:]
+	}
+
+	return typ, nil
+}
+
+// staticMethod returns the type of the method with the given name without its
+// receiver. Methods with pointer receivers are included for structs since they
+// are available whenever the struct is addressable.
+func staticMethod(typ reflect.Type, name string) (reflect.Type, bool) {
+	if name == "" {
+		return nil, false
+	}
+
+	method, ok := typ.MethodByName(name)
+	if !ok && typ.Kind() == reflect.Struct {
+		method, ok = reflect.PtrTo(typ).MethodByName(name)
+	}
+
+	if !ok {
+		return nil, false
+	}
+
+	// Methods of interface types don't include the receiver.
+	if typ.Kind() == reflect.Interface {
+		return method.Type, true
+	}
+
+	var in, out []reflect.Type
+	for i := 1; i < method.Type.NumIn(); i++ {
+		in = append(in, method.Type.In(i))
+	}
+	for i := 0; i < method.Type.NumOut(); i++ {
+		out = append(out, method.Type.Out(i))
+	}
+
+	return reflect.FuncOf(in, out, method.Type.IsVariadic()), true
+}
+
+// typeOfCall validates the arguments of a function call component and returns
+// the type of the value returned by the function.
+func typeOfCall(typ reflect.Type, head P, mid, args string) (reflect.Type, error) {
+	if !isGetterType(typ) {
+		return nil, fmt.Errorf("invalid return signature for function '%s' at '%s'", mid, head)
+	}
+
+	if _, err := parseArgs(typ, args); err != nil {
+		return nil, fmt.Errorf("invalid arguments for function '%s' at '%s' -> %s", mid, head, err)
+	}
+
+	return typ.Out(0), nil
+}
+
+// typeOfFields returns the type shared by all the fields of the struct.
+func typeOfFields(typ reflect.Type, head P) (reflect.Type, error) {
+	if typ.NumField() == 0 {
+		return nil, fmt.Errorf("no fields in type '%s' at '%s'", typ, head)
+	}
+
+	result := typ.Field(0).Type
+	for i := 1; i < typ.NumField(); i++ {
+		if typ.Field(i).Type != result {
+			return nil, ErrUnresolvable
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypeOf(t *testing.T) {
+	typ := reflect.TypeOf(&struct {
+		I  int
+		S  *GetStruct
+		A  []map[string]*GetStruct
+		R  [2]string
+		C  <-chan *GetStruct
+		SC chan<- int
+		U  Interface
+		V  interface{}
+		F  func() (int, error)
+		M  map[int]int
+		P  *Record
+		X  struct{ A, B int }
+		Y  struct {
+			A int
+			B string
+		}
+		Cal *CallStruct
+		EmbedStruct
+	}{})
+
+	checkTypeOf(t, typ, "I", reflect.TypeOf(0))
+	checkTypeOf(t, typ, "S", reflect.TypeOf(&GetStruct{}))
+	checkTypeOf(t, typ, "S.A", reflect.TypeOf(0))
+	checkTypeOf(t, typ, "S.B.()", reflect.TypeOf(0))
+	checkTypeOf(t, typ, "S.C()", reflect.TypeOf(0))
+	checkTypeOf(t, typ, "A.0.x.Z", reflect.TypeOf(0))
+	checkTypeOf(t, typ, "A.*.*", reflect.TypeOf(&GetStruct{}))
+	checkTypeOf(t, typ, "R.1", reflect.TypeOf(""))
+	checkTypeOf(t, typ, "C.1.A", reflect.TypeOf(0))
+	checkTypeOf(t, typ, "C.*.A", reflect.TypeOf(0))
+	checkTypeOf(t, typ, "C.?", reflect.TypeOf(&GetStruct{}))
+	checkTypeOf(t, typ, "C.@10ms", reflect.TypeOf(&GetStruct{}))
+	checkTypeOf(t, typ, "U.B.()", reflect.TypeOf(0))
+	checkTypeOf(t, typ, "F.()", reflect.TypeOf(0))
+	checkTypeOf(t, typ, "X.*", reflect.TypeOf(0))
+	checkTypeOf(t, typ, `Cal.Lookup("x").A`, reflect.TypeOf(0))
+	checkTypeOf(t, typ, "Cal.Sum(1, 2, 3)", reflect.TypeOf(0))
+	checkTypeOf(t, typ, "Cal.At(1)", reflect.TypeOf(0))
+	checkTypeOf(t, typ, "Y", reflect.TypeOf(struct {
+		A int
+		B string
+	}{}))
+	checkTypeOf(t, typ, "Z", reflect.TypeOf(0))
+	checkTypeOf(t, typ, "L.0", reflect.TypeOf(0))
+
+	typeOfFail(t, typ, "D")
+	typeOfFail(t, typ, "I.A")
+	typeOfFail(t, typ, "S.D")
+	typeOfFail(t, typ, "A.x")
+	typeOfFail(t, typ, "A.-1")
+	typeOfFail(t, typ, "R.2")
+	typeOfFail(t, typ, "C.x")
+	typeOfFail(t, typ, "SC.1")
+	typeOfFail(t, typ, "F.A")
+	typeOfFail(t, typ, "M.1")
+	typeOfFail(t, typ, "Cal.At(x)")
+	typeOfFail(t, typ, "Cal.Missing(1)")
+
+	typeOfUnresolvable(t, typ, "V.A")
+	typeOfUnresolvable(t, typ, "U.A")
+	typeOfUnresolvable(t, typ, "P.x")
+	typeOfUnresolvable(t, typ, "Y.*")
+}
+
+func checkTypeOf(t *testing.T, typ reflect.Type, path string, exp reflect.Type) {
+	if result, err := TypeOf(typ, New(path)); err != nil {
+		t.Errorf("FAIL(%s): %s -> %s", typ, path, err)
+	} else if result != exp {
+		t.Errorf("FAIL(%s): %s -> %s != %s", typ, path, result, exp)
+	}
+}
+
+func typeOfFail(t *testing.T, typ reflect.Type, path string) {
+	if result, err := TypeOf(typ, New(path)); err == nil {
+		t.Errorf("FAIL(%s): %s -> %s expected failure", typ, path, result)
+	} else if err == ErrUnresolvable {
+		t.Errorf("FAIL(%s): %s -> expected failure got Unresolvable", typ, path)
+	}
+}
+
+func typeOfUnresolvable(t *testing.T, typ reflect.Type, path string) {
+	if _, err := TypeOf(typ, New(path)); err != ErrUnresolvable {
+		t.Errorf("FAIL(%s): %s -> expected Unresolvable got %v", typ, path, err)
+	}
+}
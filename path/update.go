@@ -0,0 +1,50 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"reflect"
+)
+
+// UpdateFn computes the new value of a path location from its current value. A
+// nil return value sets the location to its zero value.
+type UpdateFn func(old interface{}) (interface{}, error)
+
+// Update replaces the first value in the given object that matches the path
+// with the value returned by fn when called with the current value. Missing
+// components are created such that fn is called with the zero value of a
+// missing location. The value is read and written within a single traversal
+// which also applies to values held in maps which are not addressable. Errors
+// returned by fn are returned as is.
+func (path P) Update(obj interface{}, fn UpdateFn) error {
+	return path.Apply(obj, updateContext(fn, false))
+}
+
+// UpdateAll replaces all the values in the given object that matches the path
+// with the value returned by fn when called with the current value of each
+// match. The update stops at the first error returned by fn.
+func (path P) UpdateAll(obj interface{}, fn UpdateFn) error {
+	return path.Apply(obj, updateContext(fn, true))
+}
+
+// updateContext returns a context which updates the first match or all the
+// matches if all is true.
+func updateContext(fn UpdateFn, all bool) *Context {
+	update := func(p P, ctx *Context) (bool, error) {
+		obj := ctx.Value()
+
+		result, err := fn(interfaceOf(obj))
+		if err != nil {
+			return false, err
+		}
+
+		value := reflect.ValueOf(result)
+		if result == nil {
+			value = reflect.Zero(obj.Type())
+		}
+
+		return all, set(p, ctx, value)
+	}
+
+	return &Context{CreateIfMissing: true, Fn: update}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	type Stats struct {
+		Count int
+		Tags  []string
+	}
+
+	obj := &struct {
+		I  int
+		M  map[string]int
+		MS map[string]Stats
+		MP map[string]*Stats
+		L  []string
+		P  *int
+	}{
+		I:  1,
+		M:  map[string]int{"x": 1},
+		MS: map[string]Stats{"a": {Count: 1}},
+		MP: map[string]*Stats{"a": {Count: 1}, "b": {Count: 2}},
+	}
+
+	incr := func(old interface{}) (interface{}, error) { return old.(int) + 1, nil }
+	tag := func(old interface{}) (interface{}, error) { return append(old.([]string), "t"), nil }
+
+	update(t, "I", obj, incr, 2)
+	update(t, "M.x", obj, incr, 2)
+	update(t, "M.y", obj, incr, 1)
+	update(t, "MS.a", obj, func(old interface{}) (interface{}, error) {
+		stats := old.(Stats)
+		stats.Count++
+		return stats, nil
+	}, Stats{Count: 2})
+	update(t, "MP.a.Count", obj, incr, 2)
+	update(t, "MP.b.Tags", obj, tag, []string{"t"})
+	update(t, "L", obj, tag, []string{"t"})
+
+	update(t, "P", obj, func(old interface{}) (interface{}, error) {
+		if old.(*int) != nil {
+			return nil, fmt.Errorf("expected nil")
+		}
+		return intPtr(1), nil
+	}, intPtr(1))
+
+	update(t, "P", obj, func(interface{}) (interface{}, error) { return nil, nil }, (*int)(nil))
+
+	if err := New("MP.*.Count").UpdateAll(obj, incr); err != nil {
+		t.Errorf("FAIL: UpdateAll(MP.*.Count) -> %s", err)
+	}
+
+	getAllInt(t, "UpdateAll", "MP.*.Count", obj, []int{3, 3})
+
+	errFail := fmt.Errorf("fail")
+	fail := func(interface{}) (interface{}, error) { return nil, errFail }
+
+	if err := New("I").Update(obj, fail); err != errFail {
+		t.Errorf("FAIL: Update(I) -> %v != %v", err, errFail)
+	}
+
+	if err := New("I").Update(obj, func(interface{}) (interface{}, error) { return "str", nil }); err != ErrInvalidType {
+		t.Errorf("FAIL: Update(I) -> %v != %v", err, ErrInvalidType)
+	}
+}
+
+func update(t *testing.T, path string, obj interface{}, fn UpdateFn, exp interface{}) {
+	if err := New(path).Update(obj, fn); err != nil {
+		t.Errorf("FAIL(%s): update -> %s", path, err)
+		return
+	}
+
+	if value, err := New(path).Get(obj); err != nil {
+		t.Errorf("FAIL(%s): get -> %s", path, err)
+
+	} else if !reflect.DeepEqual(value, exp) {
+		t.Errorf("FAIL(%s): get -> %v != %v", path, value, exp)
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rules associates path patterns to a comma separated list of checks which all
+// the values matching the path must pass. A check is written as either a name
+// or a name and a parameter separated by a '=' character (e.g. 'min=0'). The
+// built-in checks are 'required', 'min', 'max', 'len', 'oneof', 'email' and
+// 'pattern'.
+type Rules map[string]string
+
+// Check validates a value given the parameter of the check. The value is never
+// a nil pointer or interface.
+type Check func(value reflect.Value, param string) error
+
+// checks contains the built-in checks. The 'required' check is handled
+// separately since it also applies to missing and nil values.
+var checks = map[string]Check{
+	"min":     checkMin,
+	"max":     checkMax,
+	"len":     checkLen,
+	"oneof":   checkOneOf,
+	"email":   checkEmail,
+	"pattern": checkPattern,
+}
+
+// ValidateOptions controls how Validate evaluates rules.
+type ValidateOptions struct {
+
+	// Checks contains custom checks available to rules in addition to the
+	// built-in checks, which they replace if they share the same name.
+	Checks map[string]Check
+}
+
+// check returns the custom or built-in check with the given name.
+func (opts ValidateOptions) check(name string) (Check, bool) {
+	if check, ok := opts.Checks[name]; ok {
+		return check, true
+	}
+
+	check, ok := checks[name]
+	return check, ok
+}
+
+// Violation indicates that the value at the given concrete path failed a check.
+type Violation struct {
+	Path  P
+	Check string
+	Err   error
+}
+
+// Error returns a description of the violation.
+func (v Violation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Err)
+}
+
+// Validate evaluates the rules against the given object and returns a
+// violation for every value that failed a check. Wildcards in the rule paths
+// are expanded and the violations contain the concrete path of the value. Paths
+// that are missing in the object, including those below a nil value matched by
+// a wildcard, are only reported if the rule contains the 'required' check.
+// Returns an error if a rule is invalid or if a path can't be applied to the
+// object.
+func (rules Rules) Validate(obj interface{}, opts ValidateOptions) (violations []Violation, err error) {
+	var keys []string
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		result, err := validateRule(obj, New(key), rules[key], opts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule '%s' -> %s", key, err)
+		}
+
+		violations = append(violations, result...)
+	}
+
+	return
+}
+
+func validateRule(obj interface{}, path P, rule string, opts ValidateOptions) (violations []Violation, err error) {
+	var names, params []string
+	var fns []Check
+	var required bool
+
+	for _, item := range strings.Split(rule, ",") {
+		name, param := strings.TrimSpace(item), ""
+		if i := strings.Index(name, "="); i >= 0 {
+			name, param = name[:i], name[i+1:]
+		}
+
+		if name == "required" {
+			required = true
+			continue
+		}
+
+		check, ok := opts.check(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown check '%s'", name)
+		}
+
+		names, params, fns = append(names, name), append(params, param), append(fns, check)
+	}
+
+	fn := func(p P, ctx *Context) (bool, error) {
+		value := ctx.Value()
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				break
+			}
+			value = value.Elem()
+		}
+
+		if isNillable(value) && value.IsNil() || required && value.IsZero() {
+			if required {
+				violations = append(violations, Violation{p, "required", fmt.Errorf("value is required")})
+			}
+			return true, nil
+		}
+
+		for i, name := range names {
+			if err := fns[i](value, params[i]); err != nil {
+				violations = append(violations, Violation{p, name, err})
+			}
+		}
+
+		return true, nil
+	}
+
+	missing := func(p P, err error) error {
+		if err != ErrMissing {
+			return err
+		}
+
+		if required {
+			violations = append(violations, Violation{p, "required", fmt.Errorf("value is required")})
+		}
+		return nil
+	}
+
+	// Missing values are skipped by wildcards so the components that follow
+	// each wildcard are applied separately to each of its matches in order to
+	// report the missing ones.
+	var validate func(obj reflect.Value, head, tail P) error
+
+	validate = func(obj reflect.Value, head, tail P) error {
+		i := 0
+		for i < len(tail) && tail[i] != "*" {
+			i++
+		}
+
+		path := append(append(P{}, head...), tail...)
+
+		if i >= len(tail)-1 {
+			return missing(path, apply(obj, head, tail, &Context{Fn: fn}))
+		}
+
+		each := func(p P, ctx *Context) (bool, error) {
+			return true, validate(ctx.Value(), append(P{}, p...), tail[i+1:])
+		}
+
+		return missing(path, apply(obj, head, tail[:i+1], &Context{Fn: each}))
+	}
+
+	err = validate(reflect.ValueOf(obj), P{}, path)
+	return
+}
+
+// size returns the value of numbers or the length of strings and collections.
+func size(value reflect.Value) (float64, error) {
+	switch value.Kind() {
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), nil
+
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), nil
+
+	case reflect.Array, reflect.Slice, reflect.Map, reflect.Chan:
+		return float64(value.Len()), nil
+
+	default:
+		return 0, fmt.Errorf("unable to compute size of type '%s'", value.Type())
+	}
+}
+
+func sizeAndParam(value reflect.Value, param string) (float64, float64, error) {
+	n, err := size(value)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid parameter '%s' -> %s", param, err)
+	}
+
+	return n, limit, nil
+}
+
+func checkMin(value reflect.Value, param string) error {
+	n, limit, err := sizeAndParam(value, param)
+	if err == nil && n < limit {
+		err = fmt.Errorf("%v is less than %s", n, param)
+	}
+	return err
+}
+
+func checkMax(value reflect.Value, param string) error {
+	n, limit, err := sizeAndParam(value, param)
+	if err == nil && n > limit {
+		err = fmt.Errorf("%v is greater than %s", n, param)
+	}
+	return err
+}
+
+func checkLen(value reflect.Value, param string) error {
+	n, limit, err := sizeAndParam(value, param)
+	if err == nil && n != limit {
+		err = fmt.Errorf("%v is not equal to %s", n, param)
+	}
+	return err
+}
+
+func checkOneOf(value reflect.Value, param string) error {
+	str := fmt.Sprint(value.Interface())
+
+	for _, item := range strings.Fields(param) {
+		if item == str {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("'%s' is not one of '%s'", str, param)
+}
+
+func checkEmail(value reflect.Value, param string) error {
+	if value.Kind() != reflect.String {
+		return fmt.Errorf("unable to check email of type '%s'", value.Type())
+	}
+
+	if address, err := mail.ParseAddress(value.String()); err != nil || address.Address != value.String() {
+		return fmt.Errorf("'%s' is not a valid email address", value.String())
+	}
+
+	return nil
+}
+
+func checkPattern(value reflect.Value, param string) error {
+	if value.Kind() != reflect.String {
+		return fmt.Errorf("unable to check pattern of type '%s'", value.Type())
+	}
+
+	re, err := regexp.Compile(param)
+	if err != nil {
+		return fmt.Errorf("invalid pattern '%s' -> %s", param, err)
+	}
+
+	if !re.MatchString(value.String()) {
+		return fmt.Errorf("'%s' doesn't match '%s'", value.String(), param)
+	}
+
+	return nil
+}
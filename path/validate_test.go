@@ -0,0 +1,166 @@
+// Copyright (c) 2014 Datacratic. All rights reserved.
+
+package path
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type ValidateUser struct {
+	Name  string
+	Email string
+	Role  string
+	Tags  []string
+	Addr  *ValidateAddr
+}
+
+type ValidateAddr struct {
+	City string
+}
+
+type ValidateGroup struct {
+	Members []*ValidateUser
+}
+
+type ValidateGroups struct {
+	Groups []*ValidateGroup
+}
+
+func TestValidate(t *testing.T) {
+	obj := &struct {
+		Users  []*ValidateUser
+		Limits map[string]int
+		Owner  *ValidateUser
+		Config map[string]string
+	}{
+		Users: []*ValidateUser{
+			{Name: "alice", Email: "alice@example.com", Role: "admin", Tags: []string{"a"}, Addr: &ValidateAddr{"x"}},
+			{Name: "bob", Email: "bob", Role: "guest"},
+			{Email: "Carol <carol@example.com>", Role: "user"},
+			nil,
+		},
+		Limits: map[string]int{"cpu": 4, "memory": -1},
+		Config: map[string]string{"region": "us-east-1"},
+	}
+
+	rules := Rules{
+		"Users.*.Name":   "required, min=3",
+		"Users.*.Email":  "required,email",
+		"Users.*.Role":   "oneof=admin user",
+		"Users.*.Tags":   "max=1",
+		"Users.*":        "required",
+		"Limits.*":       "min=0,max=8",
+		"Owner":          "required",
+		"Owner.Name":     "min=1",
+		"Config.region":  "required,pattern=^[a-z]+-[a-z]+-[0-9]$",
+		"Config.zone":    "required",
+		"Config.profile": "len=3",
+	}
+
+	violations, err := rules.Validate(obj, ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result []string
+	for _, violation := range violations {
+		result = append(result, fmt.Sprintf("%s:%s", violation.Path, violation.Check))
+	}
+	sort.Strings(result)
+
+	exp := []string{
+		"Config.zone:required",
+		"Limits.memory:min",
+		"Owner:required",
+		"Users.1.Email:email",
+		"Users.1.Role:oneof",
+		"Users.2.Email:email",
+		"Users.2.Name:required",
+		"Users.3.Email:required",
+		"Users.3.Name:required",
+		"Users.3:required",
+	}
+
+	if !reflect.DeepEqual(result, exp) {
+		t.Errorf("FAIL: unexpected violations\n%v\n%v", result, exp)
+	}
+
+	// Values below a nil pointer are missing and reported as required.
+	violations, err = (Rules{"Users.*.Addr.City": "required"}).Validate(obj, ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result = nil
+	for _, violation := range violations {
+		result = append(result, fmt.Sprintf("%s:%s", violation.Path, violation.Check))
+	}
+
+	exp = []string{"Users.1.Addr.City:required", "Users.2.Addr.City:required", "Users.3.Addr.City:required"}
+	if !reflect.DeepEqual(result, exp) {
+		t.Errorf("FAIL: unexpected violations\n%v\n%v", result, exp)
+	}
+
+	// Values below a nil value matched by any of the wildcards are reported.
+	groups := &ValidateGroups{Groups: []*ValidateGroup{
+		nil, {Members: []*ValidateUser{nil, {Addr: &ValidateAddr{"x"}}}},
+	}}
+
+	violations, err = (Rules{"Groups.*.Members.*.Addr.City": "required"}).Validate(groups, ValidateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result = nil
+	for _, violation := range violations {
+		result = append(result, fmt.Sprintf("%s:%s", violation.Path, violation.Check))
+	}
+
+	exp = []string{"Groups.0.Members.*.Addr.City:required", "Groups.1.Members.0.Addr.City:required"}
+	if !reflect.DeepEqual(result, exp) {
+		t.Errorf("FAIL: unexpected violations\n%v\n%v", result, exp)
+	}
+
+	// Custom checks are passed through the options and replace the built-in
+	// checks of the same name.
+	opts := ValidateOptions{Checks: map[string]Check{
+		"prefix": func(value reflect.Value, param string) error {
+			if !strings.HasPrefix(value.String(), param) {
+				return fmt.Errorf("must start with '%s'", param)
+			}
+			return nil
+		},
+		"email": func(value reflect.Value, param string) error { return nil },
+	}}
+
+	violations, err = (Rules{"Users.*.Name": "prefix=a", "Users.*.Email": "email"}).Validate(obj, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result = nil
+	for _, violation := range violations {
+		result = append(result, fmt.Sprintf("%s:%s", violation.Path, violation.Check))
+	}
+
+	exp = []string{"Users.1.Name:prefix", "Users.2.Name:prefix"}
+	if !reflect.DeepEqual(result, exp) {
+		t.Errorf("FAIL: unexpected violations\n%v\n%v", result, exp)
+	}
+
+	if _, err := (Rules{"Users.*.Name": "prefix=a"}).Validate(obj, ValidateOptions{}); err == nil {
+		t.Errorf("FAIL: expected error for custom check without options")
+	}
+
+	if _, err := (Rules{"Users.*.Name": "unknown"}).Validate(obj, ValidateOptions{}); err == nil {
+		t.Errorf("FAIL: expected error for unknown check")
+	}
+
+	if _, err := (Rules{"Users.*.Missing": "required"}).Validate(obj, ValidateOptions{}); err == nil {
+		t.Errorf("FAIL: expected error for invalid path")
+	}
+}